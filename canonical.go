@@ -0,0 +1,184 @@
+package djson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonical returns the JSON Canonicalization Scheme (RFC 8785) form: keys
+// sorted by UTF-16 code units, numbers formatted as ECMAScript doubles and
+// strings with the minimal escaping. Numbers which are not finite become null.
+func (self *DynamicJSON) Canonical() []byte {
+	return appendCanonical(nil, self)
+}
+
+func appendCanonical(b []byte, value any) []byte {
+
+	switch v := value.(type) {
+	case *DynamicJSON:
+		if v.IsArray() {
+			b = append(b, '[')
+			for i := range v.values {
+				if i != 0 {
+					b = append(b, ',')
+				}
+				b = appendCanonical(b, v.at(i))
+			}
+			return append(b, ']')
+		}
+
+		type member struct {
+			key   string
+			units []uint16
+			inx   int
+		}
+		members := make([]member, 0, len(v.keys))
+		for i, key := range v.ordKeys {
+			if v.values[i] != gDeletedEntry {
+				members = append(members, member{key, utf16.Encode([]rune(key)), i})
+			}
+		}
+		slices.SortFunc(members, func(a, b member) int {
+			return slices.Compare(a.units, b.units)
+		})
+
+		b = append(b, '{')
+		for i, m := range members {
+			if i != 0 {
+				b = append(b, ',')
+			}
+			b = appendCanonicalString(b, m.key)
+			b = append(b, ':')
+			b = appendCanonical(b, v.at(m.inx))
+		}
+		return append(b, '}')
+
+	case *lazyNode:
+		return appendCanonical(b, v.materialize())
+	case string:
+		return appendCanonicalString(b, v)
+	case time.Time, Time:
+		return appendCanonicalString(b, value2string(v, ""))
+	}
+
+	if f, ok := number2float(value); ok {
+		return appendES6Number(b, f)
+	}
+	return appendScalar(b, value)
+}
+
+func number2float(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil || math.IsInf(f, 0)
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, true
+	case *big.Float:
+		f, _ := n.Float64()
+		return f, true
+	}
+	return 0, false
+}
+
+// appendES6Number formats f like ECMAScript Number.prototype.toString.
+func appendES6Number(b []byte, f float64) []byte {
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return append(b, "null"...)
+	}
+	if f == 0 {
+		// also -0
+		return append(b, '0')
+	}
+	if f < 0 {
+		b = append(b, '-')
+		f = -f
+	}
+
+	// the shortest digits d1..dk and n, the position of the decimal point
+	var buf [32]byte
+	e := strconv.AppendFloat(buf[:0], f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(string(e), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	k, n := len(digits), x+1
+
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, digits...)
+		for i := k; i < n; i++ {
+			b = append(b, '0')
+		}
+	case 0 < n && n <= 21:
+		b = append(b, digits[:n]...)
+		b = append(b, '.')
+		b = append(b, digits[n:]...)
+	case -6 < n && n <= 0:
+		b = append(b, "0."...)
+		for i := n; i < 0; i++ {
+			b = append(b, '0')
+		}
+		b = append(b, digits...)
+	default:
+		b = append(b, digits[0])
+		if k > 1 {
+			b = append(b, '.')
+			b = append(b, digits[1:]...)
+		}
+		b = append(b, 'e')
+		if n-1 > 0 {
+			b = append(b, '+')
+		}
+		b = strconv.AppendInt(b, int64(n-1), 10)
+	}
+	return b
+}
+
+// appendCanonicalString escapes only quotes, backslashes and control characters.
+func appendCanonicalString(b []byte, s string) []byte {
+
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c == '\b':
+			b = append(b, '\\', 'b')
+		case c == '\f':
+			b = append(b, '\\', 'f')
+		case c == '\n':
+			b = append(b, '\\', 'n')
+		case c == '\r':
+			b = append(b, '\\', 'r')
+		case c == '\t':
+			b = append(b, '\\', 't')
+		case c < 0x20:
+			b = appendEscapedRune(b, rune(c))
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			b = utf8.AppendRune(b, r)
+			i += size
+			continue
+		}
+		i++
+	}
+	return append(b, '"')
+}
@@ -0,0 +1,32 @@
+package djson_test
+
+import (
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+
+	// examples of RFC 8785
+	o, err := djson.Parse([]byte(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(o.Canonical()))
+
+	o, err = djson.Parse([]byte(`{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}", string(o.Canonical()))
+
+	o1, err := djson.ParseWith([]byte(`{"b":{"y":1.0,"x":[-0, 100, 1e21, 123e-9]},"a":"<&>"}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+	o2, err := djson.ParseWith([]byte(`{"a":"<&>","b":{"x":[0,1e2,1000000000000000000000,0.000000123],"y":1}}`), djson.ParseOptions{Numbers: djson.NumberNative})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"<&>","b":{"x":[0,100,1e+21,1.23e-7],"y":1}}`, string(o1.Canonical()))
+	assert.Equal(t, string(o1.Canonical()), string(o2.Canonical()))
+}
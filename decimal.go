@@ -0,0 +1,114 @@
+package djson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// GetDecimal returns the exact value of a number, nil if there is no number at path.
+func (self *DynamicJSON) GetDecimal(path string) *big.Rat {
+	v, ok := self.doOp(path, false, false, nil)
+	if !ok {
+		return nil
+	}
+	r, ok := number2rat(v)
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// GetBigInt returns an integer of any size, nil if there is no integer at path.
+func (self *DynamicJSON) GetBigInt(path string) *big.Int {
+	r := self.GetDecimal(path)
+	if r == nil || !r.IsInt() {
+		return nil
+	}
+	return new(big.Int).Set(r.Num())
+}
+
+// SetDecimal sets the number as its exact decimal text, it fails if the number
+// has no finite decimal representation like 1/3.
+func (self *DynamicJSON) SetDecimal(path string, d *big.Rat) error {
+	if self.IsFrozen() {
+		return fmt.Errorf("Modification attempt of frozen djson %s", path)
+	}
+	s, ok := decimalText(d)
+	if !ok {
+		return fmt.Errorf("%s is not a finite decimal", d.RatString())
+	}
+	self.Set(path, json.Number(s))
+	return nil
+}
+
+// AddDecimal adds d to the number at path, a missing number is 0.
+func (self *DynamicJSON) AddDecimal(path string, d *big.Rat) error {
+	return self.decimalOp(path, d, true, (*big.Rat).Add)
+}
+
+// SubDecimal subtracts d from the number at path, a missing number is 0.
+func (self *DynamicJSON) SubDecimal(path string, d *big.Rat) error {
+	return self.decimalOp(path, d, true, (*big.Rat).Sub)
+}
+
+// MulDecimal multiplies the number at path by d.
+func (self *DynamicJSON) MulDecimal(path string, d *big.Rat) error {
+	return self.decimalOp(path, d, false, (*big.Rat).Mul)
+}
+
+// CompareDecimal compares the number at path with d like big.Rat.Cmp, ok is
+// false if there is no number at path.
+func (self *DynamicJSON) CompareDecimal(path string, d *big.Rat) (cmp int, ok bool) {
+	r := self.GetDecimal(path)
+	if r == nil {
+		return 0, false
+	}
+	return r.Cmp(d), true
+}
+
+func (self *DynamicJSON) decimalOp(path string, d *big.Rat, missingZero bool, op func(z, x, y *big.Rat) *big.Rat) error {
+
+	r := self.GetDecimal(path)
+	if r == nil {
+		if _, found := self.Fetch(path); found || !missingZero {
+			return fmt.Errorf("%s is not a number", path)
+		}
+		r = new(big.Rat)
+	}
+
+	return self.SetDecimal(path, op(r, r, d))
+}
+
+// decimalText formats r without rounding, only denominators made of the
+// factors 2 and 5 have a finite decimal representation.
+func decimalText(r *big.Rat) (string, bool) {
+
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+
+	denom := new(big.Int).Set(r.Denom())
+	digits := 0
+	var rem big.Int
+	for _, f := range []int64{2, 5} {
+		n := 0
+		factor := big.NewInt(f)
+		for {
+			q, m := new(big.Int).QuoRem(denom, factor, &rem)
+			if m.Sign() != 0 {
+				break
+			}
+			denom = q
+			n++
+		}
+		digits = max(digits, n)
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+
+	s := r.FloatString(digits)
+	return strings.TrimRight(strings.TrimRight(s, "0"), "."), true
+}
@@ -0,0 +1,70 @@
+package djson_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rat(s string) *big.Rat {
+	r, _ := new(big.Rat).SetString(s)
+	return r
+}
+
+func TestDecimal(t *testing.T) {
+
+	o, err := djson.Parse([]byte(`{"a":12345678901234567.89,"b":0.1,"n":123456789012345678901234567890,"s":"1","e":1.5e2}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, rat("12345678901234567.89"), o.GetDecimal("a"))
+	assert.Equal(t, rat("150"), o.GetDecimal("e"))
+	assert.Nil(t, o.GetDecimal("s"))
+	assert.Nil(t, o.GetDecimal("missing"))
+
+	assert.Equal(t, "123456789012345678901234567890", o.GetBigInt("n").String())
+	assert.Nil(t, o.GetBigInt("a"))
+
+	require.NoError(t, o.AddDecimal("a", rat("0.11")))
+	assert.Equal(t, "12345678901234568", o.GetString("a", ""))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, o.AddDecimal("total", o.GetDecimal("b")))
+	}
+	require.NoError(t, o.SubDecimal("total", rat("0.05")))
+	require.NoError(t, o.MulDecimal("total", rat("10")))
+	assert.Equal(t, `{"a":12345678901234568,"b":0.1,"n":123456789012345678901234567890,"s":"1","e":1.5e2,"total":2.5}`, string(o.JSONLine()))
+
+	cmp, ok := o.CompareDecimal("total", rat("2.50"))
+	assert.True(t, ok)
+	assert.Equal(t, 0, cmp)
+
+	_, ok = o.CompareDecimal("s", rat("1"))
+	assert.False(t, ok)
+
+	assert.Error(t, o.AddDecimal("s", rat("1")))
+	assert.Error(t, o.MulDecimal("missing", rat("1")))
+	assert.Error(t, o.SetDecimal("x", rat("1/3")))
+	require.NoError(t, o.SetDecimal("x", rat("-1/8")))
+	assert.Equal(t, "-0.125", o.GetString("x", ""))
+
+	o.Freeze()
+	assert.Error(t, o.AddDecimal("total", rat("1")))
+	assert.Error(t, o.SetDecimal("x", rat("1")))
+	assert.Equal(t, "2.5", o.GetString("total", ""))
+}
+
+func TestDecimalParsedNumbers(t *testing.T) {
+
+	for _, mode := range []djson.NumberMode{djson.NumberNative, djson.NumberBig} {
+		o, err := djson.ParseWith([]byte(`{"p":0.1,"q":1.1}`), djson.ParseOptions{Numbers: mode})
+		require.NoError(t, err)
+
+		assert.Equal(t, rat("0.1"), o.GetDecimal("p"))
+		require.NoError(t, o.AddDecimal("p", rat("1/5")))
+		require.NoError(t, o.MulDecimal("q", rat("3")))
+		assert.Equal(t, `{"p":0.3,"q":3.3}`, string(o.JSONLine()))
+	}
+}
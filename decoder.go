@@ -0,0 +1,77 @@
+package djson
+
+import (
+	"io"
+	"iter"
+)
+
+// recordSeparator delimits JSON text sequences (RFC 7464).
+const recordSeparator = 0x1e
+
+// Decoder reads a stream of JSON documents: concatenated, separated by white
+// space or by record separators. A truncated document is an error.
+type Decoder struct {
+	p   *parseState
+	err error
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWith(r, ParseOptions{})
+}
+
+// NewDecoderWith applies opts to every document, Strict is always set.
+func NewDecoderWith(r io.Reader, opts ParseOptions) *Decoder {
+	opts.Strict = true
+	return &Decoder{p: newReaderParser(r, opts)}
+}
+
+// Next returns the next document or io.EOF at the end of the stream. The
+// decoder stops at the first error.
+func (self *Decoder) Next() (*DynamicJSON, error) {
+
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	p := self.p
+	for {
+		c, ok := p.skipSpaces()
+		if !ok {
+			self.err = io.EOF
+			if p.err != nil {
+				self.err = p.err
+			}
+			return nil, self.err
+		}
+		if c != recordSeparator {
+			break
+		}
+		p.pos++
+	}
+
+	if c, _ := p.peek(); c != '{' && c != '[' {
+		self.err = p.unexpected("'{' or '['")
+		return nil, self.err
+	}
+
+	p.nodes = 0
+	v, err := p.parseValue()
+	if err != nil {
+		self.err = err
+		return nil, err
+	}
+	return v.(*DynamicJSON), nil
+}
+
+// All iterates over the remaining documents, io.EOF is not reported.
+func (self *Decoder) All() iter.Seq2[*DynamicJSON, error] {
+
+	return func(yield func(*DynamicJSON, error) bool) {
+		for {
+			d, err := self.Next()
+			if err == io.EOF || !yield(d, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package djson_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+
+	dec := djson.NewDecoder(iotest.OneByteReader(strings.NewReader("{\"a\":1}{\"a\":2}[3]\n {\"a\":4}\x1e{\"a\":5}\n\x1e[]\n")))
+
+	var lines []string
+	for d, err := range dec.All() {
+		require.NoError(t, err)
+		lines = append(lines, string(d.JSONLine()))
+	}
+	assert.Equal(t, []string{`{"a":1}`, `{"a":2}`, `[3]`, `{"a":4}`, `{"a":5}`, `[]`}, lines)
+
+	_, err := dec.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDecoderErrors(t *testing.T) {
+
+	dec := djson.NewDecoder(strings.NewReader("{\"a\":1}\n{\"a\":"))
+
+	d, err := dec.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 1, d.GetInt("a", 0))
+
+	_, err = dec.Next()
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 2, pe.Line)
+
+	_, err = dec.Next()
+	assert.Equal(t, pe, err)
+
+	n := 0
+	for _, err = range djson.NewDecoder(strings.NewReader(`{} 12 {}`)).All() {
+		n++
+	}
+	assert.Equal(t, 2, n)
+	assert.Error(t, err)
+}
@@ -1,13 +1,14 @@
 package djson
 
 import (
-	"bytes"
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
 	"math"
+	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -20,13 +21,16 @@ import (
 )
 
 type DynamicJSON struct {
+	hash uint64 // cached Hash of a frozen document, first for the 64 bit alignment
+
 	// map only part
 	keys        map[string]uint32 // key -> to index used if it is a map
 	ordKeys     []string          // used if it is a map
 	iterCounter int               // Frozen if iterCounter < 0
 
 	// map&array part
-	values []interface{}
+	values   []interface{}
+	interner *KeyInterner // new keys are interned if set
 }
 
 func NewMap() *DynamicJSON {
@@ -68,7 +72,7 @@ func (self *DynamicJSON) Freeze() {
 
 	if self.IsArray() {
 		for i := 0; i < len(self.values); i++ {
-			if d, ok := self.values[i].(*DynamicJSON); ok {
+			if d, ok := self.at(i).(*DynamicJSON); ok {
 				d.Freeze()
 			}
 		}
@@ -79,7 +83,7 @@ func (self *DynamicJSON) Freeze() {
 				continue
 			}
 
-			if d, ok := self.values[i].(*DynamicJSON); ok {
+			if d, ok := self.at(i).(*DynamicJSON); ok {
 				d.Freeze()
 			}
 		}
@@ -136,6 +140,9 @@ func (self *DynamicJSON) set(key string, value interface{}) error {
 			return nil
 		}
 
+		if self.interner != nil {
+			key = self.interner.InternString(key)
+		}
 		inx := uint32(len(self.values))
 		self.keys[key] = inx
 		self.values = append(self.values, value)
@@ -177,7 +184,7 @@ func (self *DynamicJSON) get(key string) (interface{}, bool) {
 			return nil, false
 		}
 		if i < len(self.values) {
-			return self.values[i], true
+			return self.at(i), true
 		}
 		return nil, false
 	}
@@ -188,7 +195,7 @@ func (self *DynamicJSON) get(key string) (interface{}, bool) {
 		return nil, false
 	}
 
-	return self.values[inx], true
+	return self.at(int(inx)), true
 }
 
 func (self *DynamicJSON) Delete(path string) error {
@@ -294,7 +301,7 @@ func (self *DynamicJSON) Iterate(cb func(key string, value interface{}) bool) {
 
 	if self.IsArray() {
 		for i := 0; i < len(self.values); i++ {
-			if !cb("", self.values[i]) {
+			if !cb("", self.at(i)) {
 				break
 			}
 		}
@@ -314,7 +321,7 @@ func (self *DynamicJSON) Iterate(cb func(key string, value interface{}) bool) {
 			continue
 		}
 
-		if !cb(self.ordKeys[i], self.values[i]) {
+		if !cb(self.ordKeys[i], self.at(i)) {
 			break
 		}
 	}
@@ -342,8 +349,9 @@ func (self *DynamicJSON) visit(path string, cb func(fullpath string, value inter
 		for i := 0; i < len(self.values); i++ {
 
 			p := prefix + fmt.Sprint(i)
-			cb(p, self.values[i])
-			if d, ok := self.values[i].(*DynamicJSON); ok {
+			v := self.at(i)
+			cb(p, v)
+			if d, ok := v.(*DynamicJSON); ok {
 				d.visit(p, cb)
 			}
 		}
@@ -356,8 +364,9 @@ func (self *DynamicJSON) visit(path string, cb func(fullpath string, value inter
 		}
 
 		p := prefix + self.ordKeys[i]
-		cb(p, self.values[i])
-		if d, ok := self.values[i].(*DynamicJSON); ok {
+		v := self.at(i)
+		cb(p, v)
+		if d, ok := v.(*DynamicJSON); ok {
 			d.visit(p, cb)
 		}
 	}
@@ -433,7 +442,7 @@ func (self *DynamicJSON) NestedI(i int) *DynamicJSON {
 	}
 
 	if i < len(self.values) {
-		v, ok := self.values[i].(*DynamicJSON)
+		v, ok := self.at(i).(*DynamicJSON)
 		if ok {
 			return v
 		}
@@ -462,7 +471,7 @@ func (self *DynamicJSON) GetI(i int) any {
 	}
 
 	if i < len(self.values) {
-		return self.values[i]
+		return self.at(i)
 	}
 
 	return nil
@@ -510,7 +519,7 @@ func (self *DynamicJSON) ArrayI(i int) *DynamicJSON {
 	}
 
 	if i < len(self.values) {
-		v := self.values[i]
+		v := self.at(i)
 		a, ok := v.(*DynamicJSON)
 		if ok && a.IsArray() {
 			return a
@@ -552,7 +561,7 @@ func (self *DynamicJSON) MapI(i int) *DynamicJSON {
 	}
 
 	if i < len(self.values) {
-		v := self.values[i]
+		v := self.at(i)
 		m, ok := v.(*DynamicJSON)
 		if ok && !m.IsArray() {
 			return m
@@ -585,6 +594,14 @@ func createLevelFromNextPath(path string) *DynamicJSON {
 	return NewMap()
 }
 
+// SetInterner makes keys added by Set, including keys of containers created by
+// it, shared through ki. Parsed documents get ParseOptions.Interner.
+func (self *DynamicJSON) SetInterner(ki *KeyInterner) {
+	if self != nil {
+		self.interner = ki
+	}
+}
+
 func (self *DynamicJSON) IsFrozen() bool {
 	return self.iterCounter < 0
 }
@@ -620,6 +637,7 @@ func (self *DynamicJSON) doOp(path string, autoCreate bool, setValue bool, value
 
 			if autoCreate {
 				nextMap := createLevelFromNextPath(path)
+				nextMap.interner = level.interner
 				level.set(name, nextMap)
 				level = nextMap
 			} else {
@@ -655,6 +673,10 @@ func convertToDJ(v interface{}) interface{} {
 		return v
 	}
 
+	if _, ok := v.(*lazyNode); ok {
+		return v
+	}
+
 	if a, ok := v.([]interface{}); ok {
 		r := NewArray()
 		for _, x := range a {
@@ -706,6 +728,10 @@ func (self *DynamicJSON) GetTime(path string) time.Time {
 		return t
 	}
 
+	if t, ok := v.(Time); ok {
+		return t.Time
+	}
+
 	if s, ok := v.(string); ok {
 
 		if strings.Contains(s, ".") {
@@ -741,6 +767,13 @@ func value2int(v interface{}, defaultValue int) int {
 	if i, ok := v.(int64); ok {
 		return int(i)
 	}
+	if i, ok := v.(*big.Int); ok && i.IsInt64() {
+		return int(i.Int64())
+	}
+	if f, ok := v.(*big.Float); ok && !f.IsInf() {
+		x, _ := f.Float64()
+		return int(math.Round(x))
+	}
 	if s, ok := v.(string); ok {
 		i, err := strconv.Atoi(s)
 		if err == nil {
@@ -775,6 +808,20 @@ func (self *DynamicJSON) GetFloat(path string, defaultValue float64) float64 {
 	if f, ok := v.(float64); ok {
 		return f
 	}
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	if i, ok := v.(int); ok {
+		return float64(i)
+	}
+	if i, ok := v.(*big.Int); ok {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return f
+	}
+	if f, ok := v.(*big.Float); ok {
+		x, _ := f.Float64()
+		return x
+	}
 	if s, ok := v.(string); ok {
 		f, err := strconv.ParseFloat(s, 64)
 		if err == nil {
@@ -810,12 +857,19 @@ func value2string(v interface{}, defaultValue string) string {
 	if n, ok := v.(int64); ok {
 		return fmt.Sprint(n)
 	}
+	switch v.(type) {
+	case float64, *big.Int, *big.Float:
+		return scalar2str(v)
+	}
 	if j, ok := v.(DynamicJSON); ok {
 		return string(j.JSONLine())
 	}
 	if tm, ok := v.(time.Time); ok {
 		return tm.Format(time.RFC3339Nano)
 	}
+	if tm, ok := v.(Time); ok {
+		return tm.String()
+	}
 	return defaultValue
 
 }
@@ -839,6 +893,10 @@ func (self *DynamicJSON) GetStr(path string) string {
 	if s, ok := v.(string); ok {
 		return s
 	}
+	if t, ok := v.(Time); ok {
+		// a string before the conversion
+		return t.String()
+	}
 
 	return ""
 }
@@ -858,7 +916,7 @@ func (self *DynamicJSON) GetSlice(path string) []*DynamicJSON {
 			r := make([]*DynamicJSON, g.Len())
 
 			for i := range g.values {
-				r[i], _ = g.values[i].(*DynamicJSON)
+				r[i], _ = g.at(i).(*DynamicJSON)
 			}
 			return r
 		}
@@ -879,8 +937,8 @@ func (self *DynamicJSON) Each(path string) iter.Seq[*DynamicJSON] {
 
 		if g, ok := v.(*DynamicJSON); ok {
 			if g.IsArray() {
-				for _, x := range g.values {
-					if !yield(x.(*DynamicJSON)) {
+				for i := range g.values {
+					if !yield(g.at(i).(*DynamicJSON)) {
 						return
 					}
 				}
@@ -903,8 +961,8 @@ func (self *DynamicJSON) EachPair(path string) iter.Seq2[string, any] {
 
 		if g, ok := v.(*DynamicJSON); ok {
 			if g.IsArray() {
-				for _, x := range g.values {
-					if !yield("", x) {
+				for i := range g.values {
+					if !yield("", g.at(i)) {
 						return
 					}
 				}
@@ -924,7 +982,7 @@ func (self *DynamicJSON) EachPair(path string) iter.Seq2[string, any] {
 					continue
 				}
 
-				if !yield(g.ordKeys[i], g.values[i]) {
+				if !yield(g.ordKeys[i], g.at(i)) {
 					break
 				}
 			}
@@ -949,7 +1007,7 @@ func (self *DynamicJSON) GetStringsSlice(path string) []string {
 			r := make([]string, g.Len())
 
 			for i := range g.values {
-				r[i] = value2string(g.values[i], "")
+				r[i] = value2string(g.at(i), "")
 			}
 			return r
 		}
@@ -973,7 +1031,7 @@ func (self *DynamicJSON) GetAnySlice(path string) []any {
 			r := make([]any, g.Len())
 
 			for i := range g.values {
-				r[i] = g.values[i]
+				r[i] = g.at(i)
 			}
 			return r
 		}
@@ -997,7 +1055,7 @@ func (self *DynamicJSON) GetIntsSlice(path string) []int {
 			r := make([]int, g.Len())
 
 			for i := range g.values {
-				r[i] = value2int(g.values[i], 0)
+				r[i] = value2int(g.at(i), 0)
 			}
 			return r
 		}
@@ -1007,16 +1065,16 @@ func (self *DynamicJSON) GetIntsSlice(path string) []int {
 
 func (self *DynamicJSON) JSON() []byte {
 	var identBuf [64]byte
-	buf := &bytes.Buffer{}
-	self.writeTo(buf, true, identBuf[:0])
-	return buf.Bytes()
+	e := &encoder{opts: gDefaultEncodeOptions, indent: gPrettyIdent}
+	self.writeTo(e, true, identBuf[:0])
+	return e.buf
 }
 
 func (self *DynamicJSON) JSONLine() []byte {
 	var identBuf [64]byte
-	buf := &bytes.Buffer{}
-	self.writeTo(buf, false, identBuf[:0])
-	return buf.Bytes()
+	e := &encoder{opts: gDefaultEncodeOptions}
+	self.writeTo(e, false, identBuf[:0])
+	return e.buf
 }
 
 var gPrettyIdent = []byte{' ', ' '}
@@ -1031,229 +1089,124 @@ var gComma = []byte{','}
 var gPrettyKVSep = []byte{':', ' '}
 var gKVSep = []byte{':'}
 
-func (self *DynamicJSON) writeTo(w *bytes.Buffer, pretty bool, ident []byte) {
-
-	var bufStorage [256]byte
+// writeTo returns true if the container is written empty.
+func (self *DynamicJSON) writeTo(e *encoder, pretty bool, ident []byte) bool {
 
 	nestedIdent := ident
 
 	if self.IsArray() {
 		if self.Len() == 0 {
-			w.Write(gEmptyArray)
-			return
+			e.write(gEmptyArray)
+			return true
 		}
-		w.Write(gArrayBegin)
+		e.write(gArrayBegin)
+		e.depth++
 
 		if pretty {
-			nestedIdent = append(ident, gPrettyIdent...)
-			w.Write(gEndLine)
-			w.Write(nestedIdent)
+			nestedIdent = append(ident, e.indent...)
+			e.write(gEndLine)
+			e.write(nestedIdent)
 
 		}
 		for idx, v := range self.values {
 			if idx != 0 {
-				w.Write(gComma)
+				e.write(gComma)
 				if pretty {
-					w.Write(gEndLine)
-					w.Write(nestedIdent)
+					e.write(gEndLine)
+					e.write(nestedIdent)
 				}
 			}
-			if container, ok := v.(*DynamicJSON); ok {
-				container.writeTo(w, pretty, nestedIdent)
-			} else {
-
-				if tm, ok := v.(time.Time); ok {
-					v = tm.Format(time.RFC3339Nano)
-				}
-
-				b, _ := njson.Append(bufStorage[:0], v, njson.EscapeHTML)
-				w.Write(b)
-			}
-		}
-		if pretty {
-			w.Write(gEndLine)
-			w.Write(ident)
-		}
-		w.Write(gArrayEnd)
-	} else { // map
-
-		if self.Len() == 0 {
-			w.Write(gEmptyMap)
-			return
+			e.value(v, pretty, nestedIdent)
 		}
-
-		w.Write(gMapBegin)
-
 		if pretty {
-			nestedIdent = append(ident, gPrettyIdent...)
-			w.Write(gEndLine)
-			w.Write(nestedIdent)
+			e.write(gEndLine)
+			e.write(ident)
 		}
-		idx := 0
-		for i, v := range self.values {
-
-			if self.values[i] == gDeletedEntry {
-				continue
-			}
+		e.depth--
+		e.write(gArrayEnd)
+		return false
+	}
 
-			if idx != 0 {
-				w.Write(gComma)
-				if pretty {
-					w.Write(gEndLine)
-					w.Write(nestedIdent)
-				}
-			}
+	// map
+	if self.Len() == 0 {
+		e.write(gEmptyMap)
+		return true
+	}
 
-			encodeString(w, self.ordKeys[i])
+	e.write(gMapBegin)
+	e.depth++
 
-			if pretty {
-				w.Write(gPrettyKVSep)
-			} else {
-				w.Write(gKVSep)
-			}
+	if pretty {
+		nestedIdent = append(ident, e.indent...)
+	}
+	idx := 0
+	for i, v := range self.values {
 
-			if container, ok := v.(*DynamicJSON); ok {
-				container.writeTo(w, pretty, nestedIdent)
-			} else {
+		if v == gDeletedEntry || e.filtering() && e.omit(v) {
+			continue
+		}
 
-				if tm, ok := v.(time.Time); ok {
-					v = tm.Format(time.RFC3339Nano)
-				}
+		// a member with an empty container is written and rolled back
+		hold := e.opts.OmitEmpty && isContainer(v)
+		mark := len(e.buf)
+		if hold {
+			e.hold++
+		}
 
-				b, _ := njson.Append(bufStorage[:0], v, njson.EscapeHTML)
-				w.Write(b)
-			}
-			idx++
+		if idx != 0 {
+			e.write(gComma)
 		}
 		if pretty {
-			w.Write(gEndLine)
-			w.Write(ident)
+			e.write(gEndLine)
+			e.write(nestedIdent)
 		}
-		w.Write(gMapEnd)
-	}
-}
-
-// ////////////////////////////////////////////////////////////////////////////////////////////////////////////////
-// ////////////////////////////////////////////////////////////////////////////////////////////////////////////////
-
-func token2value(tok *njson.Tokenizer, strData string) any {
-
-	switch tok.Kind() {
-	case njson.Array:
-		r := NewArray()
 
-		for tok.Next() {
-			if tok.Err != nil {
-				return r
-			}
+		e.string(self.ordKeys[i])
 
-			if tok.Delim == ']' {
-				return r
-			}
-
-			if tok.Delim == ',' {
-				if !tok.Next() {
-					return r
-				}
-			}
-
-			r.values = append(r.values, token2value(tok, strData))
+		if pretty {
+			e.write(gPrettyKVSep)
+		} else {
+			e.write(gKVSep)
 		}
-		return r
-	case njson.Object:
-		r := NewMap()
-
-		for tok.Next() {
-			if tok.Err != nil {
-				return r
-			}
-
-			if tok.Delim == ',' {
-				if !tok.Next() {
-					return r
-				}
-			}
-
-			if tok.Delim == '}' {
-				return r
-			}
-
-			var key string
 
-			if tok.Kind() == njson.Unescaped && len(tok.Value) > 1 {
-				end := len(strData) - tok.Remaining()
-				start := end - len(tok.Value)
-				key = strData[start+1 : end-1]
-			} else {
-				key = string(tok.String())
-			}
-
-			tok.Next()
-
-			if !tok.Next() {
-				return r
+		empty := e.value(v, pretty, nestedIdent)
+		if hold {
+			e.hold--
+			if empty {
+				e.buf = e.buf[:mark]
+				continue
 			}
-
-			value := token2value(tok, strData)
-			r.set(key, value)
+			e.grown()
 		}
-		return r
-
-	case njson.False:
-		return false
-	case njson.True:
+		idx++
+	}
+	e.depth--
+	if idx == 0 {
+		e.write(gMapEnd)
 		return true
-	case njson.Null:
-		return nil
-	case njson.Unescaped:
-		end := len(strData) - tok.Remaining()
-		start := end - len(tok.Value)
-		return strData[start+1 : end-1]
-	default:
-
-		if tok.Kind()&njson.String != 0 {
-			return string(tok.String())
-		}
-
-		if tok.Kind()&njson.Num != 0 {
-			end := len(strData) - tok.Remaining()
-			start := end - len(tok.Value)
-			return json.Number(strData[start:end])
-		}
 	}
-
-	tok.Err = fmt.Errorf("unexpected kind %T %v", tok.Kind(), tok.Kind())
-	return nil
+	if pretty {
+		e.write(gEndLine)
+		e.write(ident)
+	}
+	e.write(gMapEnd)
+	return false
 }
 
-func Parse(data []byte) (*DynamicJSON, error) {
-
-	strData := string(data)
-	tok := njson.NewTokenizer(data)
-
-	tok.Next()
-	if tok.Delim != '{' && tok.Delim != '[' {
-		part := data
-		if len(part) > 50 {
-			part = part[:50]
-		}
-		return nil, fmt.Errorf("is not a map or array: %s", string(part))
-	}
-	v := token2value(tok, strData)
+// ////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// ////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-	if tok.Err != nil {
-		return nil, tok.Err
-	}
-	if r, ok := v.(*DynamicJSON); ok {
-		return r, nil
-	}
-	return nil, fmt.Errorf("Internal error %T %v", v, v)
+func Parse(data []byte) (*DynamicJSON, error) {
+	return ParseWith(data, ParseOptions{})
 }
 
 func cloneValue(value any) any {
 	if v, ok := value.(*DynamicJSON); ok {
 		return v.Clone()
 	}
+	if v, ok := value.(*lazyNode); ok {
+		return v.clone()
+	}
 	return value
 }
 
@@ -1264,6 +1217,7 @@ func (self *DynamicJSON) Clone() *DynamicJSON {
 
 	if self.IsArray() {
 		x := NewArray()
+		x.interner = self.interner
 		for _, v := range self.values {
 			x.values = append(x.values, cloneValue(v))
 		}
@@ -1271,6 +1225,7 @@ func (self *DynamicJSON) Clone() *DynamicJSON {
 	}
 
 	x := NewMap()
+	x.interner = self.interner
 	for i, v := range self.values {
 
 		if self.values[i] == gDeletedEntry {
@@ -1303,9 +1258,10 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 		if len(self.values) != len(o.values) {
 			return false
 		}
-		for i, value1 := range self.values {
+		for i := range self.values {
 
-			value2 := o.values[i]
+			value1 := self.at(i)
+			value2 := o.at(i)
 
 			d1, ok1 := value1.(*DynamicJSON)
 			d2, ok2 := value2.(*DynamicJSON)
@@ -1315,7 +1271,7 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 					return false
 				}
 			} else {
-				if !reflect.DeepEqual(value1, value2) {
+				if !reflect.DeepEqual(value1, value2) && !numbersEqual(value1, value2) {
 					return false
 				}
 			}
@@ -1336,7 +1292,7 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 		}
 
 		key1 := self.ordKeys[i]
-		value1 := self.values[i]
+		value1 := self.at(i)
 
 		inx, ok := o.keys[key1]
 
@@ -1344,7 +1300,7 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 			return false
 		}
 
-		value2 := o.values[inx]
+		value2 := o.at(int(inx))
 
 		d1, ok1 := value1.(*DynamicJSON)
 		d2, ok2 := value2.(*DynamicJSON)
@@ -1354,7 +1310,7 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 				return false
 			}
 		} else {
-			if !reflect.DeepEqual(value1, value2) {
+			if !reflect.DeepEqual(value1, value2) && !numbersEqual(value1, value2) {
 				return false
 			}
 		}
@@ -1362,15 +1318,34 @@ func (self *DynamicJSON) IsEqual(o *DynamicJSON) bool {
 	return true
 }
 
-func scalar2str(value interface{}) string {
-	var bufStorage [256]byte
+func appendScalar(b []byte, value any) []byte {
 
-	if tm, ok := value.(time.Time); ok {
-		value = tm.Format(time.RFC3339Nano)
+	switch v := value.(type) {
+	case time.Time:
+		value = v.Format(time.RFC3339Nano)
+	case Time:
+		value = v.String()
+	case float64:
+		// JSON5 Infinity and NaN
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return append(b, "null"...)
+		}
+	case *big.Int:
+		return v.Append(b, 10)
+	case *big.Float:
+		if v.IsInf() {
+			return append(b, "null"...)
+		}
+		return v.Append(b, 'g', -1)
 	}
 
-	b, _ := njson.Append(bufStorage[:0], value, njson.EscapeHTML)
-	return string(b)
+	b, _ = njson.Append(b, value, njson.EscapeHTML)
+	return b
+}
+
+func scalar2str(value interface{}) string {
+	var bufStorage [256]byte
+	return string(appendScalar(bufStorage[:0], value))
 }
 
 func (self *DynamicJSON) IsEqualAsString(o *DynamicJSON) bool {
@@ -1394,9 +1369,10 @@ func (self *DynamicJSON) IsEqualAsString(o *DynamicJSON) bool {
 		if len(self.values) != len(o.values) {
 			return false
 		}
-		for i, value1 := range self.values {
+		for i := range self.values {
 
-			value2 := o.values[i]
+			value1 := self.at(i)
+			value2 := o.at(i)
 
 			d1, ok1 := value1.(*DynamicJSON)
 			d2, ok2 := value2.(*DynamicJSON)
@@ -1427,7 +1403,7 @@ func (self *DynamicJSON) IsEqualAsString(o *DynamicJSON) bool {
 		}
 
 		key1 := self.ordKeys[i]
-		value1 := self.values[i]
+		value1 := self.at(i)
 
 		inx, ok := o.keys[key1]
 
@@ -1435,7 +1411,7 @@ func (self *DynamicJSON) IsEqualAsString(o *DynamicJSON) bool {
 			return false
 		}
 
-		value2 := o.values[inx]
+		value2 := o.at(int(inx))
 
 		d1, ok1 := value1.(*DynamicJSON)
 		d2, ok2 := value2.(*DynamicJSON)
@@ -1480,9 +1456,10 @@ func (self *DynamicJSON) IsEqualCheck(o *DynamicJSON) bool {
 			// Debugf("diff: array(%d) != array(%d)", len(self.values), len(o.values))
 			return false
 		}
-		for i, value1 := range self.values {
+		for i := range self.values {
 
-			value2 := o.values[i]
+			value1 := self.at(i)
+			value2 := o.at(i)
 
 			d1, ok1 := value1.(*DynamicJSON)
 			d2, ok2 := value2.(*DynamicJSON)
@@ -1525,7 +1502,7 @@ func (self *DynamicJSON) IsEqualCheck(o *DynamicJSON) bool {
 		}
 
 		key1 := self.ordKeys[i]
-		value1 := self.values[i]
+		value1 := self.at(i)
 
 		inx, ok := o.keys[key1]
 
@@ -1534,7 +1511,7 @@ func (self *DynamicJSON) IsEqualCheck(o *DynamicJSON) bool {
 			return false
 		}
 
-		value2 := o.values[inx]
+		value2 := o.at(int(inx))
 
 		d1, ok1 := value1.(*DynamicJSON)
 		d2, ok2 := value2.(*DynamicJSON)
@@ -1558,83 +1535,124 @@ func (self *DynamicJSON) IsEqualCheck(o *DynamicJSON) bool {
 
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func FromResponse(resp *http.Response, err0 error) (response *DynamicJSON, err error) {
-	if err0 != nil {
-		return nil, err0
-	}
-
-	defer resp.Body.Close()
-
-	reader := resp.Body
+func responseBody(resp *http.Response) (io.ReadCloser, error) {
 	if resp.Header.Get("Content-Encoding") == "gzip" {
-		reader, err = gzip.NewReader(resp.Body)
+		reader, err := gzip.NewReader(resp.Body)
 		if err != nil {
 			return nil, fmt.Errorf("gzip: %w", err)
 		}
-		defer reader.Close()
+		return reader, nil
 	}
+	return resp.Body, nil
+}
 
-	body, err := io.ReadAll(reader)
+func FromResponse(resp *http.Response, err0 error) (response *DynamicJSON, err error) {
+	return FromResponseWith(resp, err0, ParseOptions{})
+}
 
+func FromResponseWith(resp *http.Response, err0 error, opts ParseOptions) (response *DynamicJSON, err error) {
+	if err0 != nil {
+		return nil, err0
+	}
+
+	defer resp.Body.Close()
+
+	reader, err := responseBody(resp)
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close()
+
+	r, err := ParseReaderWith(reader, opts)
 
 	if resp.StatusCode != 200 {
 		err = fmt.Errorf("%s", resp.Status)
 	}
+	return r, err
+}
 
-	r, err2 := Parse(body)
-	if err != nil {
-		err2 = err
-	}
-	return r, err2
+// FromResponseValue is FromResponse accepting any JSON text, see ParseValue
+// for reading a scalar result.
+func FromResponseValue(resp *http.Response, err0 error) (any, error) {
+	return FromResponseValueWith(resp, err0, ParseOptions{})
 }
 
-func FromResponse200(resp *http.Response, err0 error) (response *DynamicJSON, err error) {
+func FromResponseValueWith(resp *http.Response, err0 error, opts ParseOptions) (any, error) {
 	if err0 != nil {
 		return nil, err0
 	}
 
 	defer resp.Body.Close()
 
-	reader := resp.Body
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		reader, err = gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("gzip: %w", err)
-		}
-		defer reader.Close()
+	reader, err := responseBody(resp)
+	if err != nil {
+		return nil, err
 	}
+	defer reader.Close()
 
-	body, err := io.ReadAll(reader)
+	v, err := ParseReaderValueWith(reader, opts)
 
+	if resp.StatusCode != 200 {
+		err = fmt.Errorf("%s", resp.Status)
+	}
+	return v, err
+}
+
+func FromResponse200(resp *http.Response, err0 error) (response *DynamicJSON, err error) {
+	if err0 != nil {
+		return nil, err0
+	}
+
+	defer resp.Body.Close()
+
+	reader, err := responseBody(resp)
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close()
 
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("StatusCode %v", resp.StatusCode)
 	}
 
-	if len(body) <= 2 {
-		return nil, fmt.Errorf("response '%s'", body)
+	r, err := ParseReader(reader)
+	if err != nil {
+		return nil, err
 	}
 
-	return Parse(body)
+	if r.Len() == 0 {
+		return nil, fmt.Errorf("response '%s'", r.JSONLine())
+	}
+	return r, nil
 }
 
 func FromFile(filepath string) (r *DynamicJSON, err error) {
+	return FromFileWith(filepath, ParseOptions{})
+}
 
-	data, err := os.ReadFile(filepath)
+func FromFileWith(filepath string, opts ParseOptions) (r *DynamicJSON, err error) {
+
+	f, err := os.Open(filepath)
 	if err != nil {
 		return nil, err
 	}
-	r, err = Parse(data)
+	defer f.Close()
+
+	r, err = ParseReaderWith(f, opts)
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.File = filepath
+	}
 	return
 }
 
 func FromFolder(path string) ([]*DynamicJSON, error) {
+	return FromFolderWith(path, ParseOptions{})
+}
+
+// FromFolderWith loads also .jsonc and .json5 files if opts.JSON5 is set.
+func FromFolderWith(path string, opts ParseOptions) ([]*DynamicJSON, error) {
 
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -1644,12 +1662,17 @@ func FromFolder(path string) ([]*DynamicJSON, error) {
 	var objects []*DynamicJSON
 
 	for _, e := range entries {
-		if !strings.HasSuffix(e.Name(), ".json") {
+		ext := filepath.Ext(e.Name())
+		if ext != ".json" && !(opts.JSON5 && (ext == ".jsonc" || ext == ".json5")) {
 			continue
 		}
-		r, err := FromFile(filepath.Join(path, e.Name()))
+		r, err := FromFileWith(filepath.Join(path, e.Name()), opts)
 
 		if err != nil {
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				return nil, err
+			}
 			return nil, fmt.Errorf("%s: %w", e.Name(), err)
 		}
 
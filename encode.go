@@ -0,0 +1,225 @@
+package djson
+
+import (
+	"bytes"
+	"io"
+)
+
+// UTF8Policy selects what happens to invalid UTF-8 and to lone UTF-16
+// surrogate escapes.
+type UTF8Policy int
+
+const (
+	// Parsing keeps invalid bytes and replaces lone surrogates by U+FFFD,
+	// encoding replaces both by U+FFFD.
+	UTF8Default UTF8Policy = iota
+	// Both are replaced by U+FFFD.
+	UTF8Replace
+	// Both are an error.
+	UTF8Reject
+	// Both are kept, a lone surrogate is kept as its WTF-8 bytes which are
+	// encoded back as the escape.
+	UTF8PassThrough
+)
+
+// EncodeOptions tune Encode, the zero value gives compact JSON like JSONLine
+// but without HTML escaping.
+type EncodeOptions struct {
+	// Indent enables the indented output: every element starts on a new line
+	// beginning with Prefix followed by Indent per nesting level, like
+	// json.MarshalIndent does.
+	Indent string
+	Prefix string
+
+	// EscapeHTML escapes <, > and & for embedding into HTML.
+	EscapeHTML bool
+
+	// ASCIIOnly escapes all non ASCII characters as \uXXXX.
+	ASCIIOnly bool
+
+	TrailingNewline bool
+
+	UTF8 UTF8Policy
+
+	// The filters drop object members which are null, "" or empty objects
+	// and arrays, also ones which become empty by filtering. Array elements
+	// are kept.
+	OmitNull        bool
+	OmitEmptyString bool
+	OmitEmpty       bool
+
+	// MaxDepth replaces containers nested deeper than MaxDepth by Placeholder,
+	// a scalar value, null if nil.
+	MaxDepth    int
+	Placeholder any
+}
+
+// Options of JSON and JSONLine.
+var gDefaultEncodeOptions = EncodeOptions{EscapeHTML: true}
+
+// Output is passed to the io.Writer of WriteTo in chunks of this size.
+const writeChunkSize = 8 << 10
+
+// encoder is the state of writing one document.
+type encoder struct {
+	buf    []byte
+	w      io.Writer // nil if buf collects the whole output
+	n      int64
+	opts   EncodeOptions
+	indent []byte // of the pretty output
+	depth  int    // of open containers
+	hold   int    // buf may be rolled back, it is not flushed
+	err    error
+}
+
+// Encode returns the JSON formatted according to opts, it fails on the input
+// rejected by opts.UTF8.
+func (self *DynamicJSON) Encode(opts EncodeOptions) ([]byte, error) {
+	e := &encoder{opts: opts, indent: []byte(opts.Indent)}
+	pretty := opts.Indent != "" || opts.Prefix != ""
+	self.writeTo(e, pretty, []byte(opts.Prefix))
+	if e.err != nil {
+		return nil, e.err
+	}
+	if opts.TrailingNewline {
+		e.buf = append(e.buf, '\n')
+	}
+	return e.buf, nil
+}
+
+// AppendJSON appends the compact JSON to dst.
+func (self *DynamicJSON) AppendJSON(dst []byte) []byte {
+	var identBuf [64]byte
+	e := &encoder{buf: dst, opts: gDefaultEncodeOptions}
+	self.writeTo(e, false, identBuf[:0])
+	return e.buf
+}
+
+// WriteTo streams the compact JSON to w, it implements io.WriterTo.
+func (self *DynamicJSON) WriteTo(w io.Writer) (int64, error) {
+	return self.stream(w, false)
+}
+
+// WritePrettyTo streams the indented JSON like JSON returns.
+func (self *DynamicJSON) WritePrettyTo(w io.Writer) (int64, error) {
+	return self.stream(w, true)
+}
+
+func (self *DynamicJSON) stream(w io.Writer, pretty bool) (int64, error) {
+	var identBuf [64]byte
+	e := &encoder{buf: make([]byte, 0, writeChunkSize+writeChunkSize/4), w: w, opts: gDefaultEncodeOptions, indent: gPrettyIdent}
+	self.writeTo(e, pretty, identBuf[:0])
+	e.flush()
+	return e.n, e.err
+}
+
+func (e *encoder) write(b []byte) {
+	e.buf = append(e.buf, b...)
+	e.grown()
+}
+
+// grown passes a full chunk to the writer.
+func (e *encoder) grown() {
+	if e.w != nil && e.hold == 0 && len(e.buf) >= writeChunkSize {
+		e.flush()
+	}
+}
+
+func (e *encoder) flush() {
+	if e.w == nil || len(e.buf) == 0 {
+		return
+	}
+	if e.err == nil {
+		n, err := e.w.Write(e.buf)
+		e.n += int64(n)
+		e.err = err
+	}
+	e.buf = e.buf[:0]
+}
+
+// value writes v, it returns true if v is a container written empty.
+func (e *encoder) value(v any, pretty bool, ident []byte) bool {
+	switch v := v.(type) {
+	case *DynamicJSON:
+		if !e.cut() {
+			return v.writeTo(e, pretty, ident)
+		}
+	case *lazyNode:
+		if !e.cut() {
+			return v.writeTo(e, pretty, ident)
+		}
+	case string:
+		e.string(v)
+	default:
+		e.buf = appendScalar(e.buf, v)
+		e.grown()
+	}
+	return false
+}
+
+// acceptsRaw is true if a lazy container can be written as is.
+func (e *encoder) acceptsRaw(raw []byte) bool {
+	switch {
+	case e.opts.UTF8 != UTF8Default:
+		// lone surrogates are escaped in raw
+		return false
+	case e.filtering():
+		return false
+	case e.opts.EscapeHTML && bytes.ContainsAny(raw, "<>&"):
+		return false
+	case e.opts.ASCIIOnly:
+		for _, c := range raw {
+			if c >= 0x80 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *encoder) string(s string) {
+	var err error
+	e.buf, err = appendString(e.buf, s, &e.opts)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	e.grown()
+}
+
+func (e *encoder) filtering() bool {
+	return e.opts.OmitNull || e.opts.OmitEmptyString || e.opts.OmitEmpty || e.opts.MaxDepth > 0
+}
+
+// omit is true if an object member is dropped without writing it, empty
+// containers are dropped after writing.
+func (e *encoder) omit(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return e.opts.OmitNull
+	case string:
+		return e.opts.OmitEmptyString && v == ""
+	}
+	return false
+}
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case *DynamicJSON, *lazyNode:
+		return true
+	}
+	return false
+}
+
+// cut writes the placeholder of a container deeper than MaxDepth.
+func (e *encoder) cut() bool {
+	if e.opts.MaxDepth <= 0 || e.depth < e.opts.MaxDepth {
+		return false
+	}
+	if s, ok := e.opts.Placeholder.(string); ok {
+		e.string(s)
+	} else {
+		e.buf = appendScalar(e.buf, e.opts.Placeholder)
+		e.grown()
+	}
+	return true
+}
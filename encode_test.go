@@ -0,0 +1,180 @@
+package djson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUTF8(t *testing.T) {
+
+	raw := []byte("{\"a\":\"x\xffy\",\"b\":\"\\ud800z\"}")
+
+	o, err := djson.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "x\xffy", o.GetStr("a"))
+	assert.Equal(t, "\uFFFDz", o.GetStr("b"))
+
+	o, err = djson.ParseWith(raw, djson.ParseOptions{UTF8: djson.UTF8Replace})
+	require.NoError(t, err)
+	assert.Equal(t, "x\uFFFDy", o.GetStr("a"))
+
+	_, err = djson.ParseWith(raw, djson.ParseOptions{UTF8: djson.UTF8Reject})
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "invalid byte 0xff", pe.Actual)
+	assert.EqualValues(t, 6, pe.Offset)
+
+	_, err = djson.ParseWith([]byte(`["\udc00"]`), djson.ParseOptions{UTF8: djson.UTF8Reject})
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, `lone surrogate \udc00`, pe.Actual)
+
+	o, err = djson.ParseWith(raw, djson.ParseOptions{UTF8: djson.UTF8PassThrough})
+	require.NoError(t, err)
+	assert.Equal(t, "x\xffy", o.GetStr("a"))
+	assert.Equal(t, "\xed\xa0\x80z", o.GetStr("b"))
+
+	b, err := o.Encode(djson.EncodeOptions{UTF8: djson.UTF8PassThrough})
+	require.NoError(t, err)
+	assert.Equal(t, string(raw), string(b))
+}
+
+func TestEncodeUTF8(t *testing.T) {
+
+	o := djson.NewMap()
+	o.Set("a", "x\xffy<")
+
+	assert.Equal(t, `{"a":"x\ufffdy\u003c"}`, string(o.JSONLine()))
+
+	b, err := o.Encode(djson.EncodeOptions{UTF8: djson.UTF8Replace, EscapeHTML: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"x\ufffdy\u003c"}`, string(b))
+
+	_, err = o.Encode(djson.EncodeOptions{UTF8: djson.UTF8Reject})
+	assert.Error(t, err)
+
+	o, err = djson.ParseWith([]byte("{\"n\":{\"a\":\"\xff\"}}"), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+	_, err = o.Encode(djson.EncodeOptions{UTF8: djson.UTF8Reject})
+	assert.Error(t, err)
+}
+
+func TestEncodeOptions(t *testing.T) {
+
+	o, err := djson.ParseWith([]byte(`{"html":"<b>&</b>","text":"é😀","list":[1,{"a":[]}],"lazy":{"x":"<é>"}}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+
+	b, err := o.Encode(djson.EncodeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"html":"<b>&</b>","text":"é😀","list":[1,{"a":[]}],"lazy":{"x":"<é>"}}`, string(b))
+
+	b, err = o.Encode(djson.EncodeOptions{EscapeHTML: true, ASCIIOnly: true, TrailingNewline: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"html":"\u003cb\u003e\u0026\u003c/b\u003e","text":"\u00e9\ud83d\ude00","list":[1,{"a":[]}],"lazy":{"x":"\u003c\u00e9\u003e"}}`+"\n", string(b))
+	assert.True(t, json.Valid(b))
+
+	compact, err := o.Encode(djson.EncodeOptions{})
+	require.NoError(t, err)
+	var expected bytes.Buffer
+	require.NoError(t, json.Indent(&expected, compact, "> ", "\t"))
+
+	b, err = o.Encode(djson.EncodeOptions{Indent: "\t", Prefix: "> "})
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), string(b))
+}
+
+type countingWriter struct {
+	bytes.Buffer
+	calls int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.calls++
+	return w.Buffer.Write(b)
+}
+
+func TestWriteTo(t *testing.T) {
+
+	o := djson.NewMap()
+	for i := 0; i < 2000; i++ {
+		o.Set(fmt.Sprintf("k%d/name", i), strings.Repeat("x", i%50))
+	}
+
+	var _ io.WriterTo = o
+
+	w := &countingWriter{}
+	n, err := o.WriteTo(w)
+	require.NoError(t, err)
+	assert.Equal(t, string(o.JSONLine()), w.String())
+	assert.EqualValues(t, w.Len(), n)
+	assert.Greater(t, w.calls, 1)
+
+	w = &countingWriter{}
+	_, err = o.WritePrettyTo(w)
+	require.NoError(t, err)
+	assert.Equal(t, string(o.JSON()), w.String())
+
+	_, err = o.WriteTo(iotestErrWriter{})
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+
+	small, err := djson.Parse([]byte(`{"a":[1,"b"]}`))
+	require.NoError(t, err)
+	dst := make([]byte, 0, 64)
+	dst = append(dst, "data: "...)
+	assert.Equal(t, `data: {"a":[1,"b"]}`, string(small.AppendJSON(dst)))
+	assert.Equal(t, 0, int(testing.AllocsPerRun(10, func() { small.AppendJSON(dst[:0]) })))
+}
+
+type iotestErrWriter struct{}
+
+func (iotestErrWriter) Write(b []byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
+
+func TestEncodeFilters(t *testing.T) {
+
+	o, err := djson.ParseWith([]byte(`{"a":null,"b":"","c":{},"d":[],"e":{"x":null,"y":[]},"f":[null,"",{}],"g":{"h":{"i":{"j":1}}},"k":0}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+	source := string(o.JSONLine())
+
+	b, err := o.Encode(djson.EncodeOptions{OmitNull: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":"","c":{},"d":[],"e":{"y":[]},"f":[null,"",{}],"g":{"h":{"i":{"j":1}}},"k":0}`, string(b))
+
+	b, err = o.Encode(djson.EncodeOptions{OmitNull: true, OmitEmptyString: true, OmitEmpty: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"f":[null,"",{}],"g":{"h":{"i":{"j":1}}},"k":0}`, string(b))
+
+	b, err = o.Encode(djson.EncodeOptions{MaxDepth: 2, Placeholder: "..."})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":null,"b":"","c":{},"d":[],"e":{"x":null,"y":"..."},"f":[null,"","..."],"g":{"h":"..."},"k":0}`, string(b))
+
+	b, err = o.Encode(djson.EncodeOptions{OmitNull: true, MaxDepth: 1, Indent: " "})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n \"b\": \"\",\n \"c\": null,\n \"d\": null,\n \"e\": null,\n \"f\": null,\n \"g\": null,\n \"k\": 0\n}", string(b))
+
+	b, err = djson.NewMap().Encode(djson.EncodeOptions{OmitNull: true, Indent: " "})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(b))
+
+	o2, err := djson.Parse([]byte(`{"a":null}`))
+	require.NoError(t, err)
+	b, err = o2.Encode(djson.EncodeOptions{OmitNull: true, Indent: " "})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(b))
+
+	o2, err = djson.ParseWith([]byte(`{"a":{"b":{"c":{"d":null}}},"e":[{}],"f":{"g":{},"h":1}}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+	b, err = o2.Encode(djson.EncodeOptions{OmitNull: true, OmitEmpty: true, Indent: " "})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n \"e\": [\n  {}\n ],\n \"f\": {\n  \"h\": 1\n }\n}", string(b))
+
+	assert.Equal(t, source, string(o.JSONLine()))
+}
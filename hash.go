@@ -0,0 +1,102 @@
+package djson
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Hash returns a digest consistent with IsEqual: the order of map keys and
+// the spelling of numbers do not matter. It is cached on frozen documents.
+func (self *DynamicJSON) Hash() uint64 {
+
+	frozen := self != nil && self.IsFrozen()
+	if frozen {
+		if h := atomic.LoadUint64(&self.hash); h != 0 {
+			return h
+		}
+	}
+
+	h := fnv.New64a()
+	self.Digest(h)
+	sum := h.Sum64()
+
+	if frozen {
+		atomic.StoreUint64(&self.hash, sum)
+	}
+	return sum
+}
+
+// Digest writes the data hashed by Hash into h.
+func (self *DynamicJSON) Digest(h hash.Hash) {
+	d := digest{h: h}
+	d.value(self)
+}
+
+type digest struct {
+	h   hash.Hash
+	buf []byte
+}
+
+func (d *digest) tag(c byte, n int) {
+	d.buf = append(d.buf[:0], c)
+	d.buf = binary.AppendUvarint(d.buf, uint64(n))
+	d.h.Write(d.buf)
+}
+
+func (d *digest) bytes(c byte, s string) {
+	d.tag(c, len(s))
+	d.h.Write([]byte(s))
+}
+
+func (d *digest) value(value any) {
+
+	switch v := value.(type) {
+	case *DynamicJSON:
+		if v == nil {
+			d.tag('N', 0)
+			return
+		}
+		if v.IsArray() {
+			d.tag('a', len(v.values))
+			for i := range v.values {
+				d.value(v.at(i))
+			}
+			return
+		}
+
+		inx := make([]int, 0, len(v.keys))
+		for _, i := range v.keys {
+			inx = append(inx, int(i))
+		}
+		slices.SortFunc(inx, func(a, b int) int {
+			return strings.Compare(v.ordKeys[a], v.ordKeys[b])
+		})
+		d.tag('m', len(inx))
+		for _, i := range inx {
+			d.bytes('k', v.ordKeys[i])
+			d.value(v.at(i))
+		}
+	case *lazyNode:
+		d.value(v.materialize())
+	case nil:
+		d.tag('N', 0)
+	case bool:
+		if v {
+			d.tag('T', 0)
+		} else {
+			d.tag('F', 0)
+		}
+	case string:
+		d.bytes('s', v)
+	default:
+		if r, ok := number2rat(v); ok {
+			d.bytes('n', r.RatString())
+			return
+		}
+		d.bytes('x', string(appendScalar(nil, v)))
+	}
+}
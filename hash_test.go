@@ -0,0 +1,52 @@
+package djson_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+
+	o1, err := djson.Parse([]byte(`{"a":1,"b":{"x":[1,2.50,"s"],"y":null},"c":true}`))
+	require.NoError(t, err)
+	o2, err := djson.ParseWith([]byte(`{"c":true,"b":{"y":null,"x":[1.0,25e-1,"s"]},"a":1}`), djson.ParseOptions{Numbers: djson.NumberNative, Lazy: true})
+	require.NoError(t, err)
+
+	assert.True(t, o1.IsEqual(o2))
+	assert.Equal(t, o1.Hash(), o2.Hash())
+	assert.Equal(t, o1.Nested("b").Hash(), o2.Nested("b").Hash())
+
+	raw := []byte(`{"x":0.1,"y":[1.1,-2.675e-3]}`)
+	j, err := djson.Parse(raw)
+	require.NoError(t, err)
+	for _, mode := range []djson.NumberMode{djson.NumberNative, djson.NumberBig} {
+		o, err := djson.ParseWith(raw, djson.ParseOptions{Numbers: mode})
+		require.NoError(t, err)
+		assert.Equal(t, j.Hash(), o.Hash())
+	}
+
+	h1, h2 := sha256.New(), sha256.New()
+	o1.Digest(h1)
+	o2.Digest(h2)
+	assert.Equal(t, h1.Sum(nil), h2.Sum(nil))
+
+	for _, raw := range []string{
+		`{"a":1,"b":{"x":[2.50,1,"s"],"y":null},"c":true}`,
+		`{"a":"1","b":{"x":[1,2.50,"s"],"y":null},"c":true}`,
+		`{"a":1,"b":{"x":[1,2.50,"s"]},"c":true}`,
+		`{"a":1,"b":{"x":[1,2.50,"s"],"y":null},"d":true}`,
+	} {
+		o, err := djson.Parse([]byte(raw))
+		require.NoError(t, err)
+		assert.NotEqual(t, o1.Hash(), o.Hash(), raw)
+	}
+
+	o1.Freeze()
+	h := o1.Hash()
+	assert.Equal(t, h, o1.Hash())
+	assert.Equal(t, h, o1.Clone().Hash())
+}
@@ -0,0 +1,87 @@
+package djson
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// KeyInterner shares object key strings between documents, so documents of
+// the same schema keep one copy of every key. It is safe for concurrent use
+// and is meant to be long lived: interned keys are never released.
+type KeyInterner struct {
+	mu    sync.RWMutex
+	keys  map[string]string
+	bytes int64
+
+	hits  atomic.Int64
+	saved atomic.Int64
+}
+
+// InternStats describe the effect of a KeyInterner.
+type InternStats struct {
+	Keys       int   // distinct keys
+	Bytes      int64 // total length of distinct keys
+	Hits       int64 // keys found already interned
+	SavedBytes int64 // total length of keys which were not allocated again
+}
+
+func NewKeyInterner() *KeyInterner {
+	return &KeyInterner{keys: make(map[string]string)}
+}
+
+// Intern returns the shared copy of key.
+func (self *KeyInterner) Intern(key []byte) string {
+
+	self.mu.RLock()
+	s, ok := self.keys[string(key)]
+	self.mu.RUnlock()
+	if ok {
+		self.hits.Add(1)
+		self.saved.Add(int64(len(s)))
+		return s
+	}
+
+	return self.add(string(key))
+}
+
+func (self *KeyInterner) InternString(key string) string {
+
+	self.mu.RLock()
+	s, ok := self.keys[key]
+	self.mu.RUnlock()
+	if ok {
+		if unsafe.StringData(s) != unsafe.StringData(key) {
+			// not the shared copy itself
+			self.hits.Add(1)
+			self.saved.Add(int64(len(s)))
+		}
+		return s
+	}
+
+	return self.add(key)
+}
+
+func (self *KeyInterner) add(key string) string {
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if s, ok := self.keys[key]; ok {
+		return s
+	}
+	self.keys[key] = key
+	self.bytes += int64(len(key))
+	return key
+}
+
+func (self *KeyInterner) Stats() InternStats {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	return InternStats{
+		Keys:       len(self.keys),
+		Bytes:      self.bytes,
+		Hits:       self.hits.Load(),
+		SavedBytes: self.saved.Load(),
+	}
+}
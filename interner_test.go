@@ -0,0 +1,51 @@
+package djson_test
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyInterner(t *testing.T) {
+
+	ki := djson.NewKeyInterner()
+	opts := djson.ParseOptions{Interner: ki}
+
+	var wg sync.WaitGroup
+	docs := make([]*djson.DynamicJSON, 8)
+	for i := range docs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, err := djson.ParseWith([]byte(`{"name":"x","tags":[{"name":"y"}]}`), opts)
+			assert.NoError(t, err)
+			docs[i] = d
+		}()
+	}
+	wg.Wait()
+
+	stats := ki.Stats()
+	assert.Equal(t, 2, stats.Keys)
+	assert.EqualValues(t, 8, stats.Bytes)
+	assert.EqualValues(t, 8*3-2, stats.Hits)
+	assert.EqualValues(t, 8*3*4-8, stats.SavedBytes)
+
+	keyData := func(d *djson.DynamicJSON) *byte {
+		return unsafe.StringData(d.Keys()[0])
+	}
+	assert.Equal(t, keyData(docs[0]), keyData(docs[7]))
+	assert.Equal(t, keyData(docs[0]), keyData(docs[3].Nested("tags/0")))
+
+	c := docs[0].Clone()
+	c.Set("extra/name", 1)
+	assert.Equal(t, keyData(docs[0]), keyData(c.Nested("extra")))
+	assert.Equal(t, 3, ki.Stats().Keys)
+
+	d := djson.NewMap()
+	d.SetInterner(ki)
+	d.Set(string([]byte("tags")), 1)
+	assert.Equal(t, unsafe.StringData(docs[0].Keys()[1]), keyData(d))
+}
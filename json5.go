@@ -0,0 +1,236 @@
+package djson
+
+import (
+	"math"
+	"math/big"
+	"unicode/utf8"
+)
+
+// JSON5 (https://spec.json5.org) extensions of parseState, they are enabled by ParseOptions.JSON5.
+
+// skipSpecial5 skips a comment or a JSON5 specific white space at the current position.
+func (p *parseState) skipSpecial5(c byte) bool {
+
+	switch c {
+	case '\v', '\f':
+		p.pos++
+		return true
+	case '/':
+		if p.peekString("//") {
+			p.pos += 2
+			for {
+				c, ok := p.peek()
+				if !ok || c == '\n' {
+					return true
+				}
+				p.pos++
+			}
+		}
+		if p.peekString("/*") {
+			p.pos += 2
+			for !p.peekString("*/") {
+				c, ok := p.peek()
+				if !ok {
+					if p.opts.Strict && p.err == nil {
+						// reported by the next unexpected call
+						p.err = p.unexpected("'*/'")
+					}
+					return true
+				}
+				p.pos++
+				if c == '\n' {
+					p.line++
+					p.lineStart = p.offset()
+				}
+			}
+			p.pos += 2
+			return true
+		}
+	case 0xc2, 0xe2, 0xef:
+		// NBSP, LS, PS and BOM
+		for _, s := range [...]string{"\u00a0", "\u2028", "\u2029", "\ufeff"} {
+			if p.peekString(s) {
+				p.pos += len(s)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseKey5 parses a single quoted or an unquoted object key.
+func (p *parseState) parseKey5(c byte) ([]byte, error) {
+
+	if c == '\'' {
+		p.pos++
+		return p.parseString('\'')
+	}
+
+	if !isIdentStart5(c) {
+		return nil, p.unexpected("key")
+	}
+
+	offset, line, lineStart := p.offset(), p.line, p.lineStart
+	p.tmp = p.tmp[:0]
+	for {
+		c, ok := p.peek()
+		if !ok || !(isIdentStart5(c) || isDigit(c)) {
+			if p.opts.UTF8 == UTF8Reject || p.opts.UTF8 == UTF8Replace {
+				return p.validUTF8(p.tmp, offset, line, lineStart)
+			}
+			return p.tmp, nil
+		}
+		p.tmp = append(p.tmp, c)
+		p.pos++
+		if max := p.opts.Limits.MaxStringLen; max > 0 && len(p.tmp) > max {
+			return nil, p.limitError("MaxStringLen", int64(max))
+		}
+	}
+}
+
+// Non ASCII letters are accepted without checking their unicode category.
+func isIdentStart5(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$' || c >= 0x80
+}
+
+func (p *parseState) parseEscape5(c byte) error {
+
+	switch c {
+	case '\'':
+		p.tmp = append(p.tmp, c)
+	case 'v':
+		p.tmp = append(p.tmp, '\v')
+	case '0':
+		if c, ok := p.peek(); ok && isDigit(c) {
+			return p.unexpected("escape character")
+		}
+		p.tmp = append(p.tmp, 0)
+	case 'x':
+		r, err := p.parseHex(2)
+		if err != nil {
+			return err
+		}
+		p.tmp = utf8.AppendRune(p.tmp, r)
+	case '\r':
+		// line continuation
+		if c, ok := p.peek(); ok && c == '\n' {
+			p.pos++
+		}
+		p.line++
+		p.lineStart = p.offset()
+	case '\n':
+		p.line++
+		p.lineStart = p.offset()
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		p.pos--
+		return p.unexpected("escape character")
+	default:
+		p.pos--
+		switch {
+		case p.peekString("\u2028"), p.peekString("\u2029"):
+			// line continuation
+			p.pos += 3
+		default:
+			// the character itself, a multi byte one is copied by parseString
+			p.tmp = append(p.tmp, c)
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// parseNumber5 accepts hexadecimal numbers, leading and trailing decimal
+// points, an explicit plus sign, Infinity and NaN. Finite numbers are
+// normalized to the JSON syntax, Infinity and NaN become float64.
+func (p *parseState) parseNumber5() (any, error) {
+
+	p.tmp = p.tmp[:0]
+
+	c, _ := p.peek()
+	if c == '+' || c == '-' {
+		if c == '-' {
+			p.tmp = append(p.tmp, c)
+		}
+		p.pos++
+		c, _ = p.peek()
+	}
+
+	switch c {
+	case 'I':
+		if err := p.parseLiteral("Infinity"); err != nil {
+			return nil, err
+		}
+		if len(p.tmp) != 0 {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case 'N':
+		return math.NaN(), p.parseLiteral("NaN")
+	}
+
+	if p.peekString("0x") || p.peekString("0X") {
+		p.pos += 2
+		sign := len(p.tmp)
+		for {
+			c, ok := p.peek()
+			if !ok || hexDigit(c) < 0 {
+				break
+			}
+			p.tmp = append(p.tmp, c)
+			p.pos++
+		}
+		if len(p.tmp) == sign {
+			return nil, p.unexpected("hex digit")
+		}
+		n, _ := new(big.Int).SetString(string(p.tmp), 16)
+		return p.number(n.Append(p.tmp[:0], 10)), nil
+	}
+
+	// leading zeros are dropped, missing digits around the decimal point are added
+	sign := len(p.tmp)
+	digits := 0
+	for {
+		c, ok := p.peek()
+		if !ok || c != '0' {
+			break
+		}
+		p.pos++
+		digits++
+	}
+	p.scanDigits()
+	digits += len(p.tmp) - sign
+	if len(p.tmp) == sign {
+		p.tmp = append(p.tmp, '0')
+	}
+	intPart := len(p.tmp)
+
+	c, ok := p.peek()
+	if ok && c == '.' {
+		p.tmp = append(p.tmp, c)
+		p.pos++
+		p.scanDigits()
+		digits += len(p.tmp) - intPart - 1
+		if len(p.tmp) == intPart+1 {
+			p.tmp = p.tmp[:intPart]
+		}
+	}
+
+	if digits == 0 {
+		return nil, p.unexpected("digit")
+	}
+
+	if c, ok = p.peek(); ok && (c == 'e' || c == 'E') {
+		p.tmp = append(p.tmp, c)
+		p.pos++
+		if c, ok = p.peek(); ok && (c == '+' || c == '-') {
+			p.tmp = append(p.tmp, c)
+			p.pos++
+		}
+		if c, ok = p.peek(); !ok || !isDigit(c) {
+			return nil, p.unexpected("digit")
+		}
+		p.scanDigits()
+	}
+
+	return p.number(p.tmp), nil
+}
@@ -0,0 +1,82 @@
+package djson_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSON5(t *testing.T) {
+
+	raw := "\ufeff// service config\n" +
+		"{\n" +
+		"  /* listen\n     address */\n" +
+		"  host: 'local\\'host',\n" +
+		"  $port: +8080,\n" +
+		"  \"ratio\": .5,\n" +
+		"  big: 0xFF,\n" +
+		"  trail: 5.,\n" +
+		"  list: [1, 2, 3,],\n" +
+		"  text: 'a\\\n b \\x41 \"q\"',\n" +
+		"  inf: -Infinity,\n" +
+		"}\n"
+
+	o, err := djson.ParseWith([]byte(raw), djson.ParseOptions{JSON5: true, Strict: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "local'host", o.GetStr("host"))
+	assert.Equal(t, 8080, o.GetInt("$port", 0))
+	assert.Equal(t, 0.5, o.GetFloat("ratio", 0))
+	assert.Equal(t, 255, o.GetInt("big", 0))
+	assert.Equal(t, 3, o.Nested("list").Len())
+	assert.Equal(t, `a b A "q"`, o.GetStr("text"))
+	assert.True(t, math.IsInf(o.GetFloat("inf", 0), -1))
+
+	assert.Equal(t, `{"host":"local'host","$port":8080,"ratio":0.5,"big":255,"trail":5,"list":[1,2,3],"text":"a b A \"q\"","inf":null}`,
+		string(o.JSONLine()))
+
+	_, err = djson.Parse([]byte(raw))
+	assert.Error(t, err)
+
+	_, err = djson.ParseJSON5([]byte("{\n// comment\n  a: 1,\n  b: [1,,2]}"))
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 4, pe.Line)
+	assert.Equal(t, "value", pe.Expected)
+
+	_, err = djson.ParseWith([]byte("{a:1} /* never closed"), djson.ParseOptions{JSON5: true, Strict: true})
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "'*/'", pe.Expected)
+	assert.Equal(t, "end of input", pe.Actual)
+
+	_, err = djson.ParseWith([]byte("{a:1, /* never closed"), djson.ParseOptions{JSON5: true, Strict: true})
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "'*/'", pe.Expected)
+
+	o, err = djson.ParseJSON5([]byte("{a:1} /* never closed"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, o.GetInt("a", 0))
+}
+
+func TestFromFolderJSON5(t *testing.T) {
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.jsonc"), []byte("{\"b\":2, // two\n}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.json5"), []byte(`{c:3}`), 0o644))
+
+	objects, err := djson.FromFolder(dir)
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	objects, err = djson.FromFolderWith(dir, djson.ParseOptions{JSON5: true})
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, 2, objects[1].GetInt("b", 0))
+	assert.Equal(t, 3, objects[2].GetInt("c", 0))
+}
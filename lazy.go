@@ -0,0 +1,109 @@
+package djson
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// lazyNode is a nested container kept as raw input by the lazy parse mode.
+// It is parsed once on the first access, so concurrent reads of a document
+// stay safe.
+type lazyNode struct {
+	raw      []byte
+	opts     *ParseOptions
+	verbatim bool // raw is valid JSON which can be written as is
+	spaces   bool // raw is not compact
+
+	once sync.Once
+	d    atomic.Pointer[DynamicJSON] // the parsed raw, nil until the first access
+}
+
+// parseLazy validates the next container without building it.
+func (p *parseState) parseLazy() (any, error) {
+
+	p.mark = p.pos
+	spaces, cut := p.spaces, p.cut
+
+	p.nodes-- // counted again by parseValue
+	p.rewrite = false
+	err := p.skipValue()
+
+	raw := p.buf[p.mark:p.pos]
+	p.mark = -1
+	if err != nil {
+		return nil, err
+	}
+
+	if p.r != nil {
+		raw = bytes.Clone(raw)
+	}
+
+	if p.lazyOpts == nil {
+		opts := p.opts
+		opts.Limits = Limits{} // checked already
+		p.lazyOpts = &opts
+	}
+
+	return &lazyNode{
+		raw:      raw,
+		opts:     p.lazyOpts,
+		verbatim: !p.opts.JSON5 && p.cut == cut && !p.rewrite,
+		spaces:   p.spaces != spaces,
+	}, nil
+}
+
+// materialize returns the parsed container, the same one on every call.
+func (self *lazyNode) materialize() *DynamicJSON {
+	self.once.Do(func() {
+		v, _ := newBytesParser(self.raw, *self.opts).parseValue()
+		if d, ok := v.(*DynamicJSON); ok {
+			self.d.Store(d)
+		}
+	})
+	return self.d.Load()
+}
+
+// clone returns an unparsed copy, the parsed container is not shared.
+func (self *lazyNode) clone() any {
+	if d := self.d.Load(); d != nil {
+		return d.Clone()
+	}
+	return &lazyNode{raw: self.raw, opts: self.opts, verbatim: self.verbatim, spaces: self.spaces}
+}
+
+// writeTo returns true if the container is written empty, the raw text is
+// written only without filters which need it.
+func (self *lazyNode) writeTo(e *encoder, pretty bool, ident []byte) bool {
+	switch {
+	case self.d.Load() != nil || !self.verbatim || !e.acceptsRaw(self.raw):
+		// the parsed container may be modified
+		return self.materialize().writeTo(e, pretty, ident)
+	case pretty:
+		w := bytes.NewBuffer(e.buf)
+		json.Indent(w, self.raw, string(ident), string(e.indent))
+		e.buf = w.Bytes()
+		e.grown()
+	case self.spaces:
+		w := bytes.NewBuffer(e.buf)
+		json.Compact(w, self.raw)
+		e.buf = w.Bytes()
+		e.grown()
+	default:
+		e.write(self.raw)
+	}
+	return false
+}
+
+// at returns the i-th value, a lazy container is parsed on the first access.
+func (self *DynamicJSON) at(i int) any {
+	v := self.values[i]
+	if lazy, ok := v.(*lazyNode); ok {
+		if d := lazy.materialize(); d != nil {
+			return d
+		}
+		return nil
+	}
+	return v
+}
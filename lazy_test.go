@@ -0,0 +1,113 @@
+package djson_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLazy(t *testing.T) {
+
+	raw := `{"a": {"b" : [1, 2.50]}, "c":{"x":"é"}, "d":[{"e":1},{"e":2}], "f":"s"}`
+	opts := djson.ParseOptions{Lazy: true, Strict: true}
+
+	full, err := djson.Parse([]byte(raw))
+	require.NoError(t, err)
+
+	o, err := djson.ParseWith([]byte(raw), opts)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"b":[1,2.50]},"c":{"x":"é"},"d":[{"e":1},{"e":2}],"f":"s"}`, string(o.JSONLine()))
+	assert.Equal(t, string(full.JSON()), string(o.JSON()))
+
+	assert.Equal(t, "é", o.GetStr("c/x"))
+	assert.Equal(t, `{"a":{"b":[1,2.50]},"c":{"x":"é"},"d":[{"e":1},{"e":2}],"f":"s"}`, string(o.JSONLine()))
+
+	o, err = djson.ParseReaderWith(iotest.OneByteReader(strings.NewReader(raw)), opts)
+	require.NoError(t, err)
+	assert.True(t, full.IsEqual(o))
+
+	o, err = djson.ParseWith([]byte(raw), opts)
+	require.NoError(t, err)
+	var sum int
+	for d := range o.Each("d") {
+		sum += d.GetInt("e", 0)
+	}
+	assert.Equal(t, 3, sum)
+	assert.Equal(t, 2.5, o.GetFloat("a/b/1", 0))
+	assert.True(t, o.Clone().IsEqual(full))
+
+	_, err = djson.ParseWith([]byte(`{"a":{"b":[1,}}`), opts)
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.EqualValues(t, 13, pe.Offset)
+}
+
+func TestParseLazyFreeze(t *testing.T) {
+
+	o, err := djson.ParseWith([]byte(`{"a":{"b":{"c":1}}}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+
+	o.Freeze()
+	assert.True(t, o.Nested("a").IsFrozen())
+	assert.True(t, o.Nested("a/b").IsFrozen())
+
+	var paths []string
+	o.Visit(func(path string, _ any) {
+		paths = append(paths, path)
+	})
+	assert.Equal(t, []string{"a", "a/b", "a/b/c"}, paths)
+}
+
+func TestParseLazyConcurrentReads(t *testing.T) {
+
+	o, err := djson.ParseWith([]byte(`{"a":{"b":1},"c":[{"d":2}]}`), djson.ParseOptions{Lazy: true})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 1, o.GetInt("a/b", 0))
+			assert.Equal(t, 2, o.GetInt("c/0/d", 0))
+			assert.Equal(t, `{"a":{"b":1},"c":[{"d":2}]}`, string(o.JSONLine()))
+		}()
+	}
+	wg.Wait()
+
+	c := o.Clone()
+	c.Set("a/b", 3)
+	o.Set("c/0/d", 4)
+	assert.Equal(t, `{"a":{"b":1},"c":[{"d":4}]}`, string(o.JSONLine()))
+	assert.Equal(t, `{"a":{"b":3},"c":[{"d":2}]}`, string(c.JSONLine()))
+}
+
+func TestParseLazyRewrite(t *testing.T) {
+
+	for _, tc := range []struct {
+		raw  string
+		opts djson.ParseOptions
+	}{
+		{`{"x":{"a":1,"a":2}}`, djson.ParseOptions{DuplicateKeys: djson.DuplicateFirstWins}},
+		{`{"x":{"a":1,"b":0,"a":2}}`, djson.ParseOptions{}},
+		{`{"x":[{"a":1,"a":2}]}`, djson.ParseOptions{DuplicateKeys: djson.DuplicateCollect}},
+		{"{\"x\":[\"\xff\"]}", djson.ParseOptions{}},
+		{"{\"x\":[\" \"]}", djson.ParseOptions{}},
+		{`{"x":["\ud800"]}`, djson.ParseOptions{}},
+		{`{"x":["\ud800"]}`, djson.ParseOptions{UTF8: djson.UTF8PassThrough}},
+	} {
+		eager, err := djson.ParseWith([]byte(tc.raw), tc.opts)
+		require.NoError(t, err)
+
+		tc.opts.Lazy = true
+		o, err := djson.ParseWith([]byte(tc.raw), tc.opts)
+		require.NoError(t, err)
+		assert.Equal(t, string(eager.JSONLine()), string(o.JSONLine()), tc.raw)
+		assert.Equal(t, string(eager.JSON()), string(o.JSON()), tc.raw)
+	}
+}
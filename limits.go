@@ -0,0 +1,56 @@
+package djson
+
+import "fmt"
+
+// Limits protect the parser against hostile input, zero fields are unlimited
+// except MaxDepth which is defaultMaxDepth then.
+type Limits struct {
+	MaxDepth        int   // nesting of objects and arrays
+	MaxBytes        int64 // size of the input
+	MaxContainerLen int   // elements of a single object or array
+	MaxStringLen    int   // bytes of a string or a key after unescaping
+	MaxNodes        int64 // total number of values
+}
+
+// defaultMaxDepth keeps the recursive parser off the stack limit, like the
+// cap of encoding/json.
+const defaultMaxDepth = 10000
+
+// SafeLimits is a preset for untrusted input like HTTP request bodies.
+func SafeLimits() Limits {
+	return Limits{
+		MaxDepth:        128,
+		MaxBytes:        32 << 20,
+		MaxContainerLen: 1 << 20,
+		MaxStringLen:    4 << 20,
+		MaxNodes:        4 << 20,
+	}
+}
+
+// LimitError is returned when the input exceeds one of the Limits.
+type LimitError struct {
+	Limit  string // name of the Limits field
+	Max    int64
+	Offset int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("offset %d: %s %d exceeded", e.Offset, e.Limit, e.Max)
+}
+
+// nest enters a container, it fails beyond MaxDepth.
+func (p *parseState) nest() error {
+	p.depth++
+	max := p.opts.Limits.MaxDepth
+	if max <= 0 {
+		max = defaultMaxDepth
+	}
+	if p.depth > max {
+		return p.limitError("MaxDepth", int64(max))
+	}
+	return nil
+}
+
+func (p *parseState) limitError(limit string, max int64) error {
+	return &LimitError{Limit: limit, Max: max, Offset: p.offset()}
+}
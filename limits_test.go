@@ -0,0 +1,88 @@
+package djson_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLimited(t *testing.T, raw string, limits djson.Limits) *djson.LimitError {
+	t.Helper()
+
+	_, err := djson.ParseWith([]byte(raw), djson.ParseOptions{Limits: limits})
+	var le1 *djson.LimitError
+	require.ErrorAs(t, err, &le1)
+
+	_, err = djson.ParseReaderWith(strings.NewReader(raw), djson.ParseOptions{Limits: limits})
+	var le2 *djson.LimitError
+	require.ErrorAs(t, err, &le2)
+	assert.Equal(t, le1, le2)
+
+	return le1
+}
+
+func TestParseLimits(t *testing.T) {
+
+	le := parseLimited(t, strings.Repeat("[", 1000000), djson.SafeLimits())
+	assert.Equal(t, "MaxDepth", le.Limit)
+	assert.EqualValues(t, 129, le.Offset)
+
+	le = parseLimited(t, `{"a":"`+strings.Repeat("x", 100)+`"}`, djson.Limits{MaxBytes: 50})
+	assert.Equal(t, "MaxBytes", le.Limit)
+
+	le = parseLimited(t, `[1,2,3,4]`, djson.Limits{MaxContainerLen: 3})
+	assert.Equal(t, "MaxContainerLen", le.Limit)
+
+	le = parseLimited(t, `{"a":"12345\n"}`, djson.Limits{MaxStringLen: 5})
+	assert.Equal(t, "MaxStringLen", le.Limit)
+
+	le = parseLimited(t, `[[1],[2]]`, djson.Limits{MaxNodes: 4})
+	assert.Equal(t, "MaxNodes", le.Limit)
+	assert.Equal(t, "offset 6: MaxNodes 4 exceeded", le.Error())
+
+	raw := `{"a":[1,2,3],"b":"12345"}`
+	limits := djson.Limits{MaxDepth: 2, MaxBytes: int64(len(raw)), MaxContainerLen: 3, MaxStringLen: 5, MaxNodes: 6}
+	o, err := djson.ParseReaderWith(strings.NewReader(raw), djson.ParseOptions{Limits: limits, Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, raw, string(o.JSONLine()))
+}
+
+func TestParseDefaultDepth(t *testing.T) {
+
+	deep := strings.Repeat("[", 20000000)
+
+	le := parseLimited(t, deep, djson.Limits{})
+	assert.Equal(t, "MaxDepth", le.Limit)
+	assert.EqualValues(t, 10001, le.Offset)
+
+	_, err := djson.Parse([]byte(deep))
+	assert.Error(t, err)
+	_, _, err = djson.ParseRepair([]byte(deep))
+	assert.Error(t, err)
+	_, err = djson.ParsePaths([]byte(deep), "0/0")
+	assert.Error(t, err)
+	_, err = djson.ParseReaderValue(strings.NewReader(deep))
+	assert.Error(t, err)
+	_, err = djson.NewDecoder(strings.NewReader(deep)).Next()
+	assert.Error(t, err)
+
+	nested := strings.Repeat("[", 10000) + strings.Repeat("]", 10000)
+	_, err = djson.Parse([]byte(nested))
+	require.NoError(t, err)
+
+	opts := djson.ParseOptions{Limits: djson.Limits{MaxDepth: 2}}
+	_, _, err = djson.ParseRepairWith([]byte(`[[[1]]]`), opts)
+	assert.Error(t, err)
+	_, err = djson.ParsePathsWith([]byte(`{"a":[[1]]}`), opts, "a/0")
+	assert.Error(t, err)
+	_, err = djson.ParseReaderValueWith(strings.NewReader(`[[[1]]]`), opts)
+	assert.Error(t, err)
+	_, err = djson.NewDecoderWith(strings.NewReader(`[[[1]]]`), opts).Next()
+	assert.Error(t, err)
+	for _, err = range djson.ReadLinesWith(strings.NewReader("[[[1]]]\n"), opts) {
+		assert.Error(t, err)
+	}
+}
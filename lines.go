@@ -0,0 +1,88 @@
+package djson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ReadLines iterates over JSON Lines (NDJSON) input, blank lines are skipped.
+// Every line must be exactly one complete document.
+// Parse errors are *ParseError with Line and Offset relative to the whole input,
+// the iteration may be continued after them. A read error stops the iteration.
+func ReadLines(r io.Reader) iter.Seq2[*DynamicJSON, error] {
+	return ReadLinesWith(r, ParseOptions{})
+}
+
+// ReadLinesWith applies opts to every line, Strict is always set.
+func ReadLinesWith(r io.Reader, opts ParseOptions) iter.Seq2[*DynamicJSON, error] {
+
+	opts.Strict = true
+
+	return func(yield func(*DynamicJSON, error) bool) {
+
+		br := bufio.NewReaderSize(r, readChunkSize)
+		var line []byte
+		var offset int64
+
+		for n := 1; ; n++ {
+
+			line = line[:0]
+			var err error
+			for {
+				var part []byte
+				part, err = br.ReadSlice('\n')
+				line = append(line, part...)
+				if err != bufio.ErrBufferFull {
+					break
+				}
+			}
+
+			if len(bytes.TrimSpace(line)) != 0 {
+				d, perr := ParseWith(line, opts)
+				if perr != nil {
+					var pe *ParseError
+					if errors.As(perr, &pe) {
+						pe.Line = n
+						pe.Offset += offset
+					} else {
+						perr = fmt.Errorf("line %d: %w", n, perr)
+					}
+				}
+				if !yield(d, perr) {
+					return
+				}
+			}
+			offset += int64(len(line))
+
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("line %d: %w", n, err))
+				return
+			}
+		}
+	}
+}
+
+// LinesWriter writes documents in JSON Lines format, Flush must be called at the end.
+type LinesWriter struct {
+	w *bufio.Writer
+}
+
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{w: bufio.NewWriterSize(w, readChunkSize)}
+}
+
+func (self *LinesWriter) Write(d *DynamicJSON) error {
+	self.w.Write(d.JSONLine())
+	return self.w.WriteByte('\n')
+}
+
+func (self *LinesWriter) Flush() error {
+	return self.w.Flush()
+}
@@ -0,0 +1,87 @@
+package djson_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLines(t *testing.T) {
+
+	input := "{\"a\":1}\n\n[2]\r\n{\"a\":x}\n{\"b\":\"" + strings.Repeat("z", 100000) + "\"}"
+
+	var docs []*djson.DynamicJSON
+	var errs []error
+	for d, err := range djson.ReadLines(strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, d)
+	}
+
+	require.Len(t, docs, 3)
+	assert.Equal(t, 1, docs[0].GetInt("a", 0))
+	assert.Equal(t, 2, docs[1].GetInt("0", 0))
+	assert.Equal(t, 100000, len(docs[2].GetStr("b")))
+
+	require.Len(t, errs, 1)
+	var pe *djson.ParseError
+	require.ErrorAs(t, errs[0], &pe)
+	assert.Equal(t, 4, pe.Line)
+	assert.EqualValues(t, 19, pe.Offset)
+}
+
+func TestReadLinesStrict(t *testing.T) {
+
+	input := "{\"a\":[1,2\n{\"b\":1}{\"c\":2}\n{\"d\":3}\n"
+
+	var docs []*djson.DynamicJSON
+	var errs []*djson.ParseError
+	for d, err := range djson.ReadLines(strings.NewReader(input)) {
+		if err != nil {
+			var pe *djson.ParseError
+			require.ErrorAs(t, err, &pe)
+			errs = append(errs, pe)
+			continue
+		}
+		docs = append(docs, d)
+	}
+
+	require.Len(t, docs, 1)
+	assert.Equal(t, 3, docs[0].GetInt("d", 0))
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, 1, errs[0].Line)
+	assert.Equal(t, "end of input", errs[0].Actual)
+	assert.Equal(t, 2, errs[1].Line)
+	assert.Equal(t, "end of input", errs[1].Expected)
+	assert.EqualValues(t, 17, errs[1].Offset)
+}
+
+func TestLinesWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+	w := djson.NewLinesWriter(&buf)
+
+	o := djson.NewMap()
+	o.Set("a/b", 1)
+	require.NoError(t, w.Write(o))
+	require.NoError(t, w.Write(djson.NewArray()))
+	assert.Equal(t, 0, buf.Len())
+
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "{\"a\":{\"b\":1}}\n[]\n", buf.String())
+
+	var n int
+	for d, err := range djson.ReadLines(&buf) {
+		require.NoError(t, err)
+		assert.NotNil(t, d)
+		n++
+	}
+	assert.Equal(t, 2, n)
+}
@@ -0,0 +1,37 @@
+package djson
+
+import "fmt"
+
+// MarshalJSON implements json.Marshaler, the key order is kept.
+func (self *DynamicJSON) MarshalJSON() ([]byte, error) {
+	return self.JSONLine(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the document is replaced by data
+// which must be an object or an array. JSON null leaves it unchanged.
+func (self *DynamicJSON) UnmarshalJSON(data []byte) error {
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	if self.IsFrozen() {
+		return fmt.Errorf("Modification attempt of frozen djson")
+	}
+
+	d, err := ParseWith(data, ParseOptions{Strict: true, Interner: self.interner})
+	if err != nil {
+		return err
+	}
+	*self = *d
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, the text is the compact JSON.
+func (self *DynamicJSON) MarshalText() ([]byte, error) {
+	return self.JSONLine(), nil
+}
+
+func (self *DynamicJSON) UnmarshalText(text []byte) error {
+	return self.UnmarshalJSON(text)
+}
@@ -0,0 +1,54 @@
+package djson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	ID    int                `json:"id"`
+	Extra *djson.DynamicJSON `json:"extra"`
+	List  []*djson.DynamicJSON
+	Empty *djson.DynamicJSON `json:"empty"`
+}
+
+func TestMarshalJSON(t *testing.T) {
+
+	raw := `{"id":1,"extra":{"z":1,"a":{"y":[1,2.50,"<"],"b":null}},"List":[[],{"k":"v"}],"empty":null}`
+
+	var e event
+	require.NoError(t, json.Unmarshal([]byte(raw), &e))
+	assert.Equal(t, 1, e.ID)
+	assert.Equal(t, []string{"z", "a"}, e.Extra.Keys())
+	assert.Equal(t, "v", e.List[1].GetStr("k"))
+	assert.Nil(t, e.Empty)
+
+	b, err := json.Marshal(&e)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"extra":{"z":1,"a":{"y":[1,2.50,"\u003c"],"b":null}},"List":[[],{"k":"v"}],"empty":null}`, string(b))
+
+	var d djson.DynamicJSON
+	assert.Error(t, json.Unmarshal([]byte(`"text"`), &d))
+
+	m := map[string]*djson.DynamicJSON{}
+	require.NoError(t, json.Unmarshal([]byte(`{"a":{"x":1}}`), &m))
+	assert.Equal(t, 1, m["a"].GetInt("x", 0))
+
+	frozen := djson.NewMap()
+	frozen.Freeze()
+	assert.Error(t, frozen.UnmarshalJSON([]byte(`{}`)))
+}
+
+func TestMarshalText(t *testing.T) {
+
+	o := djson.NewMap()
+	require.NoError(t, o.UnmarshalText([]byte(`{"b":1,"a":[true]}`)))
+
+	text, err := o.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":1,"a":[true]}`, string(text))
+}
@@ -0,0 +1,109 @@
+package djson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// number converts a number text to the representation selected by ParseOptions.Numbers.
+func (p *parseState) number(text []byte) any {
+
+	if p.skipping {
+		return nil
+	}
+
+	switch p.opts.Numbers {
+	case NumberNative:
+		s := string(text)
+		if isIntegerText(text) {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i
+			}
+			return json.Number(s)
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+		return json.Number(s)
+
+	case NumberBig:
+		s := string(text)
+		if isIntegerText(text) {
+			i, _ := new(big.Int).SetString(s, 10)
+			return i
+		}
+		prec := max(uint(len(text))*4, 64)
+		f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+		if err == nil {
+			return f
+		}
+		return json.Number(s)
+	}
+
+	return json.Number(text)
+}
+
+func isIntegerText(text []byte) bool {
+	for _, c := range text {
+		if c == '.' || c == 'e' || c == 'E' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponents are limited to keep big.Rat conversions cheap.
+const maxRatExponent = 1000
+
+// number2rat converts any number representation to a rational, floats are
+// taken by their shortest decimal text, so 0.1 equals json.Number("0.1").
+func number2rat(v any) (*big.Rat, bool) {
+
+	switch n := v.(type) {
+	case json.Number:
+		return text2rat(n.String())
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, false
+		}
+		return text2rat(strconv.FormatFloat(n, 'g', -1, 64))
+	case *big.Int:
+		return new(big.Rat).SetInt(n), true
+	case *big.Float:
+		if n.IsInf() {
+			return nil, false
+		}
+		return text2rat(n.Text('g', -1))
+	}
+	return nil, false
+}
+
+func text2rat(s string) (*big.Rat, bool) {
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxRatExponent || exp < -maxRatExponent {
+			return nil, false
+		}
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// numbersEqual compares numbers regardless of their representation, so 1 and 1.0 are equal.
+func numbersEqual(a, b any) bool {
+	x, ok := number2rat(a)
+	if !ok {
+		return false
+	}
+	y, ok := number2rat(b)
+	if !ok {
+		return false
+	}
+	return x.Cmp(y) == 0
+}
@@ -0,0 +1,70 @@
+package djson_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumbers(t *testing.T) {
+
+	raw := []byte(`{"i":12,"f":1.5,"e":1e2,"huge":123456789012345678901234567890,"neg":-7}`)
+
+	o, err := djson.ParseWith(raw, djson.ParseOptions{Numbers: djson.NumberNative})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), o.Get("i"))
+	assert.Equal(t, 1.5, o.Get("f"))
+	assert.Equal(t, 100.0, o.Get("e"))
+	assert.Equal(t, json.Number("123456789012345678901234567890"), o.Get("huge"))
+	assert.Equal(t, 12, o.GetInt("i", 0))
+	assert.Equal(t, 12.0, o.GetFloat("i", 0))
+	assert.Equal(t, "1.5", o.GetString("f", ""))
+	assert.Equal(t, `{"i":12,"f":1.5,"e":100,"huge":123456789012345678901234567890,"neg":-7}`, string(o.JSONLine()))
+
+	o, err = djson.ParseWith(raw, djson.ParseOptions{Numbers: djson.NumberBig})
+	require.NoError(t, err)
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, huge, o.Get("huge"))
+	assert.IsType(t, &big.Float{}, o.Get("f"))
+	assert.Equal(t, -7, o.GetInt("neg", 0))
+	assert.Equal(t, 1.5, o.GetFloat("f", 0))
+	assert.Equal(t, "123456789012345678901234567890", o.GetString("huge", ""))
+	assert.Equal(t, `{"i":12,"f":1.5,"e":100,"huge":123456789012345678901234567890,"neg":-7}`, string(o.JSONLine()))
+
+	o2, err := djson.Parse(raw)
+	require.NoError(t, err)
+	assert.True(t, o.IsEqual(o2))
+}
+
+func TestIsEqualNumbers(t *testing.T) {
+
+	a, err := djson.Parse([]byte(`{"x":1,"y":[2.50]}`))
+	require.NoError(t, err)
+	b, err := djson.Parse([]byte(`{"x":1.0,"y":[25e-1]}`))
+	require.NoError(t, err)
+	assert.True(t, a.IsEqual(b))
+
+	b.Set("x", 1)
+	assert.True(t, a.IsEqual(b))
+
+	b.Set("x", json.Number("1.0000000000000000001"))
+	assert.False(t, a.IsEqual(b))
+
+	b.Set("x", "1")
+	assert.False(t, a.IsEqual(b))
+
+	raw := []byte(`{"x":0.1,"y":[1.1,-2.675e-3]}`)
+	j, err := djson.Parse(raw)
+	require.NoError(t, err)
+	n, err := djson.ParseWith(raw, djson.ParseOptions{Numbers: djson.NumberNative})
+	require.NoError(t, err)
+	g, err := djson.ParseWith(raw, djson.ParseOptions{Numbers: djson.NumberBig})
+	require.NoError(t, err)
+	assert.True(t, j.IsEqual(n))
+	assert.True(t, j.IsEqual(g))
+	assert.True(t, n.IsEqual(g))
+}
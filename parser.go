@@ -0,0 +1,1037 @@
+package djson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const readChunkSize = 64 * 1024
+
+// parseState is a recursive descent JSON parser working either over a complete
+// byte slice or over an io.Reader which is consumed in chunks.
+type parseState struct {
+	r   io.Reader
+	buf []byte // buf[pos:] is not consumed yet
+	pos int
+	off int64 // input offset of buf[0]
+	eof bool
+	err error // read error
+
+	line      int   // number of new lines consumed
+	lineStart int64 // input offset of the current line
+
+	tmp []byte // scratch space for strings and numbers
+
+	opts  ParseOptions
+	depth int
+	nodes int64
+
+	// lazy mode state
+	skipping bool // values are validated but not built
+	mark     int  // start of the skipped value in buf or -1
+	spaces   int  // number of skipped white space characters
+	cut      bool // a truncated container was accepted
+	rewrite  bool // the skipped value would not be written as its input
+	lazyOpts *ParseOptions
+
+	repairs *[]Repair // repair mode of ParseRepair
+}
+
+// ParseOptions tune the parser, the zero value gives the behaviour of Parse.
+type ParseOptions struct {
+	// Strict requires the input to be exactly one complete JSON value,
+	// otherwise truncated documents, missing or extra commas and leading
+	// zeros are accepted and trailing data is ignored.
+	Strict bool
+
+	// JSON5 accepts JSON5 and JSONC: comments, trailing commas, single quoted
+	// strings, unquoted keys and JSON5 numbers.
+	JSON5 bool
+
+	DuplicateKeys DuplicateKeys
+
+	Numbers NumberMode
+
+	Limits Limits
+
+	// UTF8 selects the handling of invalid UTF-8 in strings and of escaped
+	// lone surrogates.
+	UTF8 UTF8Policy
+
+	// Interner shares keys of parsed objects, also with keys added by Set later.
+	Interner *KeyInterner
+
+	// TimeLayouts converts string values which are exactly a time in one of
+	// the layouts, e.g. time.RFC3339, into Time. Strings which would not be
+	// written back the same, like "00:00:00.500Z" for time.RFC3339Nano, stay.
+	TimeLayouts []string
+
+	// TimePaths limits TimeLayouts to values at the given paths, a "*" path
+	// element matches any key or array index.
+	TimePaths []string
+
+	// Lazy builds only the top level container, nested containers are kept
+	// as raw input and parsed on the first access. The input passed to
+	// ParseWith must not be modified afterwards. Concurrent reads stay safe.
+	Lazy bool
+}
+
+// NumberMode selects the Go type of parsed numbers, a number which does not
+// fit into the selected type stays json.Number.
+type NumberMode int
+
+const (
+	NumberJSON   NumberMode = iota // json.Number, the text as is
+	NumberNative                   // int64 for integers, float64 otherwise
+	NumberBig                      // *big.Int for integers, *big.Float otherwise
+)
+
+// DuplicateKeys selects what happens to a key repeated in the same object.
+type DuplicateKeys int
+
+const (
+	DuplicateLastWins  DuplicateKeys = iota // the last value is kept at the position of the first key
+	DuplicateFirstWins                      // the first value is kept
+	DuplicateError                          // parsing fails with ParseError
+	DuplicateCollect                        // all values are collected into an array
+)
+
+func newBytesParser(data []byte, opts ParseOptions) *parseState {
+	return &parseState{buf: data, eof: true, opts: opts, mark: -1}
+}
+
+func newReaderParser(r io.Reader, opts ParseOptions) *parseState {
+	return &parseState{r: r, buf: make([]byte, 0, readChunkSize), opts: opts, mark: -1}
+}
+
+func (p *parseState) offset() int64 {
+	return p.off + int64(p.pos)
+}
+
+// fill reads the next chunk from the reader, the consumed part of the buffer
+// is dropped unless it is marked.
+func (p *parseState) fill() bool {
+
+	if p.r == nil || p.eof {
+		return false
+	}
+
+	keep := p.pos
+	if p.mark >= 0 {
+		keep = p.mark
+		p.mark = 0
+	}
+
+	if keep > 0 {
+		n := copy(p.buf, p.buf[keep:])
+		p.off += int64(keep)
+		p.buf = p.buf[:n]
+		p.pos -= keep
+	}
+
+	if cap(p.buf)-len(p.buf) < readChunkSize/4 {
+		buf := make([]byte, len(p.buf), 2*cap(p.buf)+readChunkSize)
+		copy(buf, p.buf)
+		p.buf = buf
+	}
+
+	end := cap(p.buf)
+	if max := p.opts.Limits.MaxBytes; max > 0 {
+		// one byte more to detect the overflow
+		end = int(min(int64(end), max-p.off+1))
+	}
+
+	for {
+		n, err := p.r.Read(p.buf[len(p.buf):end])
+		p.buf = p.buf[:len(p.buf)+n]
+
+		if max := p.opts.Limits.MaxBytes; max > 0 && p.off+int64(len(p.buf)) > max {
+			p.buf = p.buf[:max-p.off]
+			p.eof = true
+			p.err = &LimitError{Limit: "MaxBytes", Max: max, Offset: max}
+			return p.pos < len(p.buf)
+		}
+
+		if err != nil {
+			p.eof = true
+			if err != io.EOF {
+				p.err = err
+			}
+			return n > 0
+		}
+
+		if n > 0 {
+			return true
+		}
+	}
+}
+
+func (p *parseState) peek() (byte, bool) {
+	if p.pos >= len(p.buf) && !p.fill() {
+		return 0, false
+	}
+	return p.buf[p.pos], true
+}
+
+func (p *parseState) skipSpaces() (byte, bool) {
+	for {
+		for p.pos < len(p.buf) {
+			switch c := p.buf[p.pos]; c {
+			case ' ', '\t', '\r':
+				p.pos++
+				p.spaces++
+			case '\n':
+				p.pos++
+				p.spaces++
+				p.line++
+				p.lineStart = p.offset()
+			default:
+				if p.opts.JSON5 && p.skipSpecial5(c) {
+					p.spaces++
+					continue
+				}
+				return c, true
+			}
+		}
+		if !p.fill() {
+			return 0, false
+		}
+	}
+}
+
+// ParseError describes a syntax error found while parsing, Line and Column are 1-based,
+// Column is counted in bytes.
+type ParseError struct {
+	File     string
+	Offset   int64
+	Line     int
+	Column   int
+	Expected string
+	Actual   string
+	Snippet  string // input around the error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File)
+		b.WriteByte(':')
+	}
+	fmt.Fprintf(&b, "%d:%d (offset %d): expected %s, found %s", e.Line, e.Column, e.Offset, e.Expected, e.Actual)
+	if e.Snippet != "" {
+		fmt.Fprintf(&b, " near %q", e.Snippet)
+	}
+	return b.String()
+}
+
+const snippetRadius = 24
+
+// unexpected returns an error for the input at the current position.
+func (p *parseState) unexpected(expected string) error {
+
+	if p.err != nil {
+		return p.err
+	}
+
+	actual := "end of input"
+	if c, ok := p.peek(); ok {
+		actual = describeByte(c)
+	}
+	return p.syntaxError(expected, actual)
+}
+
+// syntaxError builds a ParseError for the current position.
+func (p *parseState) syntaxError(expected, actual string) *ParseError {
+
+	offset := p.offset()
+	e := &ParseError{
+		Offset:   offset,
+		Line:     p.line + 1,
+		Column:   int(offset-p.lineStart) + 1,
+		Expected: expected,
+		Actual:   actual,
+	}
+
+	from := max(p.pos-snippetRadius, 0)
+	if start := p.lineStart - p.off; start > int64(from) {
+		from = int(start)
+	}
+	to := min(p.pos+snippetRadius, len(p.buf))
+	if i := bytes.IndexByte(p.buf[p.pos:to], '\n'); i >= 0 {
+		to = p.pos + i
+	}
+	e.Snippet = string(p.buf[from:to])
+
+	return e
+}
+
+func describeByte(c byte) string {
+	if 0x20 <= c && c < 0x7f {
+		return fmt.Sprintf("'%c'", c)
+	}
+	return fmt.Sprintf("byte 0x%02x", c)
+}
+
+// parseDocument parses the top level map or array.
+func (p *parseState) parseDocument() (*DynamicJSON, error) {
+
+	c, _ := p.skipSpaces()
+	if c != '{' && c != '[' {
+		return nil, p.unexpected("'{' or '['")
+	}
+
+	v, err := p.parseTop()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DynamicJSON), nil
+}
+
+// parseTop parses any top level value.
+func (p *parseState) parseTop() (any, error) {
+
+	if max := p.opts.Limits.MaxBytes; max > 0 && p.r == nil && int64(len(p.buf)) > max {
+		return nil, &LimitError{Limit: "MaxBytes", Max: max, Offset: max}
+	}
+
+	v, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.opts.Strict {
+		if _, ok := p.skipSpaces(); ok || p.err != nil {
+			return nil, p.unexpected("end of input")
+		}
+	}
+
+	if d, ok := v.(*DynamicJSON); ok && len(p.opts.TimeLayouts) != 0 && len(p.opts.TimePaths) != 0 {
+		convertTimes(d, &p.opts)
+	}
+	return v, nil
+}
+
+func (p *parseState) parseValue() (any, error) {
+
+	c, _ := p.skipSpaces()
+
+	p.nodes++
+	if max := p.opts.Limits.MaxNodes; max > 0 && p.nodes > max {
+		return nil, p.limitError("MaxNodes", max)
+	}
+
+	if p.opts.JSON5 {
+		switch c {
+		case '\'':
+			p.pos++
+			b, err := p.parseString('\'')
+			if err != nil || p.skipping {
+				return nil, err
+			}
+			return string(b), nil
+		case '+', '-', '.', 'I', 'N', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return p.parseNumber5()
+		}
+	}
+
+	switch c {
+	case '{', '[':
+		if p.opts.Lazy && p.depth == 1 && !p.skipping {
+			return p.parseLazy()
+		}
+		p.pos++
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		var v any
+		var err error
+		if c == '{' {
+			v, err = p.parseObject()
+		} else {
+			v, err = p.parseArray()
+		}
+		p.depth--
+		if r, ok := v.(*DynamicJSON); ok && r != nil {
+			// set after parsing, the parsed keys are interned already
+			r.interner = p.opts.Interner
+		}
+		return v, err
+	case '"':
+		p.pos++
+		b, err := p.parseString('"')
+		if err != nil || p.skipping {
+			return nil, err
+		}
+		s := string(b)
+		if len(p.opts.TimeLayouts) != 0 && len(p.opts.TimePaths) == 0 {
+			if t, ok := parseTime(s, p.opts.TimeLayouts); ok {
+				return t, nil
+			}
+		}
+		return s, nil
+	case 't':
+		return true, p.parseLiteral("true")
+	case 'f':
+		return false, p.parseLiteral("false")
+	case 'n':
+		return nil, p.parseLiteral("null")
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		b, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return p.number(b), nil
+	}
+
+	return nil, p.unexpected("value")
+}
+
+// truncated handles the end of input inside of a container. Unless the strict
+// mode is on, the container is returned as is, like the tokenizer based parser did.
+func (p *parseState) truncated(r *DynamicJSON, expected string) (any, error) {
+	if p.opts.Strict || p.err != nil {
+		return nil, p.unexpected(expected)
+	}
+	p.cut = true
+	switch {
+	case r == nil:
+	case r.IsArray():
+		p.repair(RepairClosedArray)
+	default:
+		p.repair(RepairClosedObject)
+	}
+	return r, nil
+}
+
+// tolerant is true if the input the tokenizer based parser accepted is accepted
+// too: missing commas, a leading or a trailing comma and leading zeros. The
+// tolerated input is never kept as raw text.
+func (p *parseState) tolerant() bool {
+	if p.opts.Strict || p.repairs != nil {
+		return false
+	}
+	p.rewrite = true
+	return true
+}
+
+func startsValue(c byte) bool {
+	switch c {
+	case '"', '{', '[', '-', 't', 'f', 'n':
+		return true
+	}
+	return isDigit(c)
+}
+
+// skipValue validates the next value without building it.
+func (p *parseState) skipValue() error {
+	p.skipping = true
+	_, err := p.parseValue()
+	p.skipping = false
+	return err
+}
+
+// In the skipping mode containers are not built, r is nil.
+func (p *parseState) parseObject() (any, error) {
+
+	var r *DynamicJSON
+	if !p.skipping {
+		r = NewMap()
+	}
+
+	c, ok := p.skipSpaces()
+	if !ok {
+		return p.truncated(r, "string key or '}'")
+	}
+	if c == '}' {
+		p.pos++
+		return r, nil
+	}
+	if c == ',' && p.tolerant() {
+		p.pos++
+	}
+
+	var collected map[string]bool
+	var seen map[string]bool // keys of a skipped object
+	n := 0
+
+	for {
+		c, ok = p.skipSpaces()
+		if !ok {
+			p.repair(RepairDroppedComma)
+			return p.truncated(r, "string key")
+		}
+
+		keyOffset, keyLine, keyLineStart := p.offset(), p.line, p.lineStart
+
+		var b []byte
+		var err error
+		switch {
+		case c == '"':
+			p.pos++
+			b, err = p.parseString('"')
+		case c == '}' && (p.opts.JSON5 || p.repairs != nil || p.tolerant()):
+			if !p.opts.JSON5 {
+				p.repair(RepairDroppedComma)
+			}
+			p.pos++
+			return r, nil
+		case p.repairs != nil && c == ',':
+			p.repair(RepairDroppedComma)
+			p.pos++
+			continue
+		case p.opts.JSON5:
+			b, err = p.parseKey5(c)
+		default:
+			return nil, p.unexpected("string key")
+		}
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		var duplicate bool
+		switch {
+		case r != nil && p.opts.Interner != nil:
+			key = p.opts.Interner.Intern(b)
+			_, duplicate = r.keys[key]
+		case r != nil:
+			key = string(b)
+			_, duplicate = r.keys[key]
+		case p.opts.DuplicateKeys == DuplicateError || p.mark >= 0:
+			// a lazy container with duplicates is not written as its input
+			key = string(b)
+			duplicate = seen[key]
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+			seen[key] = true
+			p.rewrite = p.rewrite || duplicate
+		}
+
+		if duplicate && p.opts.DuplicateKeys == DuplicateError {
+			e := p.syntaxError("unique key", fmt.Sprintf("duplicate key %q", key))
+			e.Offset, e.Line, e.Column = keyOffset, keyLine+1, int(keyOffset-keyLineStart)+1
+			return nil, e
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			p.repair(RepairDroppedKey)
+			return p.truncated(r, "':'")
+		}
+		if c != ':' {
+			return nil, p.unexpected("':'")
+		}
+		p.pos++
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			p.repair(RepairDroppedKey)
+			return p.truncated(r, "value")
+		}
+		if p.repairs != nil && (c == '}' || c == ',') {
+			p.repair(RepairDroppedKey)
+			p.pos++
+			if c == '}' {
+				return r, nil
+			}
+			continue
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			if p.repairable() {
+				p.repair(RepairDroppedValue)
+				return p.truncated(r, "value")
+			}
+			return nil, err
+		}
+
+		switch {
+		case r == nil:
+		case !duplicate || p.opts.DuplicateKeys == DuplicateLastWins:
+			r.set(key, value)
+		case p.opts.DuplicateKeys == DuplicateCollect:
+			prev, _ := r.get(key)
+			if collected[key] {
+				prev.(*DynamicJSON).values = append(prev.(*DynamicJSON).values, value)
+				break
+			}
+			if collected == nil {
+				collected = make(map[string]bool)
+			}
+			collected[key] = true
+			all := NewArray()
+			all.values = append(all.values, prev, value)
+			r.set(key, all)
+		}
+
+		n++
+		if max := p.opts.Limits.MaxContainerLen; max > 0 && n > max {
+			return nil, p.limitError("MaxContainerLen", int64(max))
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "',' or '}'")
+		}
+		p.pos++
+		switch c {
+		case ',':
+			continue
+		case '}':
+			return r, nil
+		}
+		p.pos--
+		if c == ']' && p.repairs != nil {
+			// the closing bracket is missing
+			p.repair(RepairClosedObject)
+			return r, nil
+		}
+		if c == '"' && p.tolerant() {
+			continue
+		}
+		return nil, p.unexpected("',' or '}'")
+	}
+}
+
+func (p *parseState) parseArray() (any, error) {
+
+	var r *DynamicJSON
+	if !p.skipping {
+		r = NewArray()
+	}
+	n := 0
+
+	c, ok := p.skipSpaces()
+	if !ok {
+		return p.truncated(r, "value or ']'")
+	}
+	if c == ']' {
+		p.pos++
+		return r, nil
+	}
+	if c == ',' && p.tolerant() {
+		p.pos++
+	}
+
+	for {
+		c, ok = p.skipSpaces()
+		if !ok {
+			p.repair(RepairDroppedComma)
+			return p.truncated(r, "value")
+		}
+		if c == ']' && (p.opts.JSON5 || p.repairs != nil || p.tolerant()) {
+			if !p.opts.JSON5 {
+				p.repair(RepairDroppedComma)
+			}
+			p.pos++
+			return r, nil
+		}
+		if p.repairs != nil && c == ',' {
+			p.repair(RepairDroppedComma)
+			p.pos++
+			continue
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			if p.repairable() {
+				p.repair(RepairDroppedValue)
+				return p.truncated(r, "value")
+			}
+			return nil, err
+		}
+		if r != nil {
+			r.values = append(r.values, value)
+		}
+
+		n++
+		if max := p.opts.Limits.MaxContainerLen; max > 0 && n > max {
+			return nil, p.limitError("MaxContainerLen", int64(max))
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "',' or ']'")
+		}
+		p.pos++
+		switch c {
+		case ',':
+			continue
+		case ']':
+			return r, nil
+		}
+		p.pos--
+		if c == '}' && p.repairs != nil {
+			// the closing bracket is missing
+			p.repair(RepairClosedArray)
+			return r, nil
+		}
+		if startsValue(c) && p.tolerant() {
+			continue
+		}
+		return nil, p.unexpected("',' or ']'")
+	}
+}
+
+func (p *parseState) parseLiteral(lit string) error {
+	for i := 0; i < len(lit); i++ {
+		c, ok := p.peek()
+		if !ok || c != lit[i] {
+			return p.unexpected(fmt.Sprintf("'%c' of %s", lit[i], lit))
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// parseNumber returns the number text, it is valid until the next call.
+func (p *parseState) parseNumber() ([]byte, error) {
+
+	p.tmp = p.tmp[:0]
+
+	c, _ := p.peek()
+	if c == '-' {
+		p.tmp = append(p.tmp, c)
+		p.pos++
+	}
+
+	c, ok := p.peek()
+	switch {
+	case !ok || !isDigit(c):
+		return nil, p.unexpected("digit")
+	case c == '0':
+		p.pos++
+		if c, ok = p.peek(); ok && isDigit(c) && p.tolerant() {
+			// leading zeros are dropped
+			for ok && c == '0' {
+				p.pos++
+				c, ok = p.peek()
+			}
+			if ok && isDigit(c) {
+				p.scanDigits()
+				break
+			}
+		}
+		p.tmp = append(p.tmp, '0')
+	default:
+		p.scanDigits()
+	}
+
+	if c, ok = p.peek(); ok && c == '.' {
+		p.tmp = append(p.tmp, c)
+		p.pos++
+		if c, ok = p.peek(); !ok || !isDigit(c) {
+			return nil, p.unexpected("digit")
+		}
+		p.scanDigits()
+	}
+
+	if c, ok = p.peek(); ok && (c == 'e' || c == 'E') {
+		p.tmp = append(p.tmp, c)
+		p.pos++
+		if c, ok = p.peek(); ok && (c == '+' || c == '-') {
+			p.tmp = append(p.tmp, c)
+			p.pos++
+		}
+		if c, ok = p.peek(); !ok || !isDigit(c) {
+			return nil, p.unexpected("digit")
+		}
+		p.scanDigits()
+	}
+
+	return p.tmp, nil
+}
+
+func (p *parseState) scanDigits() {
+	for {
+		c, ok := p.peek()
+		if !ok || !isDigit(c) {
+			return
+		}
+		p.tmp = append(p.tmp, c)
+		p.pos++
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// parseString is called after the opening quote and returns the unescaped
+// string, it is valid until the next call.
+func (p *parseState) parseString(quote byte) ([]byte, error) {
+
+	offset, line, lineStart := p.offset(), p.line, p.lineStart
+	b, err := p.scanString(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.mark >= 0 && !p.rewrite && !verbatimString(b) {
+		p.rewrite = true
+	}
+
+	if p.opts.UTF8 == UTF8Reject || p.opts.UTF8 == UTF8Replace && !p.skipping {
+		return p.validUTF8(b, offset, line, lineStart)
+	}
+	return b, nil
+}
+
+// verbatimString is true if the encoder writes the unescaped characters of s as is.
+func verbatimString(s []byte) bool {
+	return utf8.Valid(s) && !bytes.Contains(s, []byte("\u2028")) && !bytes.Contains(s, []byte("\u2029"))
+}
+
+// validUTF8 applies the Reject and Replace policies to a string which starts
+// at the given position.
+func (p *parseState) validUTF8(b []byte, offset int64, line int, lineStart int64) ([]byte, error) {
+
+	if utf8.Valid(b) {
+		return b, nil
+	}
+
+	if p.opts.UTF8 == UTF8Reject {
+		i := 0
+		for {
+			r, size := utf8.DecodeRune(b[i:])
+			if r == utf8.RuneError && size == 1 {
+				break
+			}
+			i += size
+		}
+		e := p.syntaxError("valid UTF-8", fmt.Sprintf("invalid byte 0x%02x", b[i]))
+		e.Offset, e.Line, e.Column = offset, line+1, int(offset-lineStart)+1
+		return nil, e
+	}
+
+	r := make([]byte, 0, len(b)+8)
+	for len(b) > 0 {
+		c, size := utf8.DecodeRune(b)
+		if c == utf8.RuneError && size == 1 {
+			r = utf8.AppendRune(r, utf8.RuneError)
+		} else {
+			r = append(r, b[:size]...)
+		}
+		b = b[size:]
+	}
+	return r, nil
+}
+
+func (p *parseState) scanString(quote byte) ([]byte, error) {
+
+	p.tmp = p.tmp[:0]
+
+	for {
+		start := p.pos
+		for p.pos < len(p.buf) {
+			c := p.buf[p.pos]
+			if c == quote || c == '\\' || c < 0x20 {
+				break
+			}
+			p.pos++
+		}
+
+		if max := p.opts.Limits.MaxStringLen; max > 0 && len(p.tmp)+p.pos-start > max {
+			return nil, p.limitError("MaxStringLen", int64(max))
+		}
+
+		if p.pos == len(p.buf) {
+			p.tmp = append(p.tmp, p.buf[start:]...)
+			if !p.fill() {
+				if p.repairable() {
+					p.repair(RepairClosedString)
+					return p.tmp, nil
+				}
+				return nil, p.unexpected(describeByte(quote))
+			}
+			continue
+		}
+
+		c := p.buf[p.pos]
+
+		if c == quote {
+			if len(p.tmp) == 0 {
+				// fast path, no escapes and no chunk boundaries
+				p.pos++
+				return p.buf[start : p.pos-1], nil
+			}
+			p.tmp = append(p.tmp, p.buf[start:p.pos]...)
+			p.pos++
+			return p.tmp, nil
+		}
+
+		if c < 0x20 {
+			return nil, p.unexpected("string character")
+		}
+
+		p.tmp = append(p.tmp, p.buf[start:p.pos]...)
+		p.pos++
+		if err := p.parseEscape(); err != nil {
+			if p.repairable() {
+				// the incomplete escape sequence is dropped
+				p.repair(RepairClosedString)
+				return p.tmp, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+func (p *parseState) parseEscape() error {
+
+	c, ok := p.peek()
+	if !ok {
+		return p.unexpected("escape character")
+	}
+	p.pos++
+
+	switch c {
+	case '"', '\\', '/':
+		p.tmp = append(p.tmp, c)
+	case 'b':
+		p.tmp = append(p.tmp, '\b')
+	case 'f':
+		p.tmp = append(p.tmp, '\f')
+	case 'n':
+		p.tmp = append(p.tmp, '\n')
+	case 'r':
+		p.tmp = append(p.tmp, '\r')
+	case 't':
+		p.tmp = append(p.tmp, '\t')
+	case 'u':
+		r1, err := p.parseHex(4)
+		if err != nil {
+			return err
+		}
+
+		if utf16.IsSurrogate(r1) {
+			if p.peekString(`\u`) && p.ensure(6) {
+				if r2, ok := hexValue(p.buf[p.pos+2 : p.pos+6]); ok {
+					if r := utf16.DecodeRune(r1, r2); r != utf8.RuneError {
+						p.pos += 6
+						p.tmp = utf8.AppendRune(p.tmp, r)
+						return nil
+					}
+				}
+			}
+			p.rewrite = true
+			switch p.opts.UTF8 {
+			case UTF8Reject:
+				return p.syntaxError("surrogate pair", fmt.Sprintf("lone surrogate \\u%04x", r1))
+			case UTF8PassThrough:
+				p.tmp = appendSurrogate(p.tmp, r1)
+				return nil
+			}
+			r1 = utf8.RuneError
+		}
+		p.tmp = utf8.AppendRune(p.tmp, r1)
+	default:
+		if p.opts.JSON5 {
+			return p.parseEscape5(c)
+		}
+		p.pos--
+		return p.unexpected("escape character")
+	}
+	return nil
+}
+
+// ensure makes at least n unconsumed bytes available in the buffer.
+func (p *parseState) ensure(n int) bool {
+	for len(p.buf)-p.pos < n {
+		if !p.fill() {
+			return false
+		}
+	}
+	return true
+}
+
+// peekString reports whether the unconsumed input starts with s.
+func (p *parseState) peekString(s string) bool {
+	return p.ensure(len(s)) && string(p.buf[p.pos:p.pos+len(s)]) == s
+}
+
+func (p *parseState) parseHex(n int) (rune, error) {
+	var r rune
+	for i := 0; i < n; i++ {
+		c, _ := p.peek()
+		d := hexDigit(c)
+		if d < 0 {
+			return 0, p.unexpected("hex digit")
+		}
+		p.pos++
+		r = r<<4 | d
+	}
+	return r, nil
+}
+
+func hexDigit(c byte) rune {
+	switch {
+	case '0' <= c && c <= '9':
+		return rune(c - '0')
+	case 'a' <= c && c <= 'f':
+		return rune(c - 'a' + 10)
+	case 'A' <= c && c <= 'F':
+		return rune(c - 'A' + 10)
+	}
+	return -1
+}
+
+func hexValue(b []byte) (rune, bool) {
+	var r rune
+	for _, c := range b {
+		d := hexDigit(c)
+		if d < 0 {
+			return 0, false
+		}
+		r = r<<4 | d
+	}
+	return r, true
+}
+
+func ParseWith(data []byte, opts ParseOptions) (*DynamicJSON, error) {
+	return newBytesParser(data, opts).parseDocument()
+}
+
+func ParseStrict(data []byte) (*DynamicJSON, error) {
+	return ParseWith(data, ParseOptions{Strict: true})
+}
+
+func ParseJSON5(data []byte) (*DynamicJSON, error) {
+	return ParseWith(data, ParseOptions{JSON5: true})
+}
+
+// ParseValue accepts any JSON text including top level scalars. The result is
+// either *DynamicJSON or one of the scalar types returned by Get. A scalar is
+// read by a type switch or by the getters of an array holding it:
+//
+//	a := djson.NewArray()
+//	a.Append(v)
+//	n := a.GetInt("0", 0)
+func ParseValue(data []byte) (any, error) {
+	return ParseValueWith(data, ParseOptions{})
+}
+
+func ParseValueWith(data []byte, opts ParseOptions) (any, error) {
+	return newBytesParser(data, opts).parseTop()
+}
+
+func ParseReaderValue(r io.Reader) (any, error) {
+	return ParseReaderValueWith(r, ParseOptions{})
+}
+
+func ParseReaderValueWith(r io.Reader, opts ParseOptions) (any, error) {
+	return newReaderParser(r, opts).parseTop()
+}
+
+func ParseReader(r io.Reader) (*DynamicJSON, error) {
+	return ParseReaderWith(r, ParseOptions{})
+}
+
+func ParseReaderWith(r io.Reader, opts ParseOptions) (*DynamicJSON, error) {
+	return newReaderParser(r, opts).parseDocument()
+}
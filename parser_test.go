@@ -0,0 +1,251 @@
+package djson_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReader(t *testing.T) {
+
+	raw := `{"a":[1,-2.5e3,true,false,null],"b\"":"xé😀\n","c":{"d":[]}}`
+
+	expected, err := djson.Parse([]byte(raw))
+	require.NoError(t, err)
+
+	o, err := djson.ParseReader(iotest.OneByteReader(strings.NewReader(raw)))
+	require.NoError(t, err)
+
+	assert.True(t, expected.IsEqual(o))
+	assert.Equal(t, "xé\U0001F600\n", o.GetStr(`b"`))
+	assert.Equal(t, -2500.0, o.GetFloat("a/1", 0))
+	assert.Equal(t, `{"a":[1,-2.5e3,true,false,null],"b\"":"xé😀\n","c":{"d":[]}}`, string(o.JSONLine()))
+
+	o, err = djson.ParseReader(iotest.OneByteReader(strings.NewReader(`["\ud83d\ude00\u00e9\ud800"]`)))
+	require.NoError(t, err)
+	assert.Equal(t, "\U0001F600é\uFFFD", o.GetStr("0"))
+}
+
+func TestParseReaderLongString(t *testing.T) {
+
+	long := strings.Repeat("0123456789", 20000)
+	raw := `{"s":"` + long + `","n":123456789}`
+
+	o, err := djson.ParseReader(iotest.HalfReader(strings.NewReader(raw)))
+	require.NoError(t, err)
+
+	assert.Equal(t, long, o.GetStr("s"))
+	assert.Equal(t, 123456789, o.GetInt("n", 0))
+}
+
+func TestParseReaderErrors(t *testing.T) {
+
+	_, err := djson.ParseReader(strings.NewReader(`{"a":tru}`))
+	assert.Error(t, err)
+
+	_, err = djson.ParseReader(strings.NewReader(`{"a":"x`))
+	assert.Error(t, err)
+
+	_, err = djson.ParseReader(strings.NewReader(`"x"`))
+	assert.Error(t, err)
+
+	_, err = djson.ParseReader(iotest.ErrReader(os.ErrClosed))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestFromFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "a.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"a":{"b":1}}`), 0o644))
+
+	o, err := djson.FromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, o.GetInt("a/b", 0))
+}
+
+func TestParseError(t *testing.T) {
+
+	_, err := djson.Parse([]byte("{\n  \"a\": 1,\n  \"b\": [1, 2 x]\n}"))
+
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 3, pe.Line)
+	assert.Equal(t, 14, pe.Column)
+	assert.EqualValues(t, 25, pe.Offset)
+	assert.Equal(t, "',' or ']'", pe.Expected)
+	assert.Equal(t, "'x'", pe.Actual)
+	assert.Equal(t, `  "b": [1, 2 x]`, pe.Snippet)
+
+	_, err = djson.Parse([]byte(`  "text"`))
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "'{' or '['", pe.Expected)
+	assert.Equal(t, 3, pe.Column)
+
+	_, err = djson.Parse([]byte(`{"a":"b`))
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "end of input", pe.Actual)
+	assert.EqualValues(t, 7, pe.Offset)
+}
+
+func TestParseErrorFile(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("[1,\n2,,3]"), 0o644))
+
+	_, err := djson.FromFile(path)
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, path, pe.File)
+	assert.Equal(t, 2, pe.Line)
+	assert.Equal(t, 3, pe.Column)
+
+	_, err = djson.FromFolder(dir)
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, path+":2:3 (offset 6): expected value, found ',' near \"2,,3]\"", err.Error())
+}
+
+func TestParseStrict(t *testing.T) {
+
+	o, err := djson.Parse([]byte(`{"a":1}garbage`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, o.GetInt("a", 0))
+
+	o, err = djson.Parse([]byte(`{"a":[1,2`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[1,2]}`, string(o.JSONLine()))
+
+	var pe *djson.ParseError
+
+	_, err = djson.ParseStrict([]byte(`{"a":1}garbage`))
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "end of input", pe.Expected)
+	assert.Equal(t, "'g'", pe.Actual)
+	assert.EqualValues(t, 7, pe.Offset)
+
+	_, err = djson.ParseStrict([]byte(`{"a":[1,2`))
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "',' or ']'", pe.Expected)
+	assert.Equal(t, "end of input", pe.Actual)
+
+	_, err = djson.ParseReaderWith(strings.NewReader(`{"a":`), djson.ParseOptions{Strict: true})
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "value", pe.Expected)
+
+	o, err = djson.ParseStrict([]byte(" {\"a\":[1,2]} \n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, o.Nested("a").Len())
+}
+
+func TestParseTolerant(t *testing.T) {
+
+	for raw, expected := range map[string]string{
+		`{"a":1,}`:       `{"a":1}`,
+		`{,"a":1 , }`:    `{"a":1}`,
+		`[1,]`:           `[1]`,
+		`[,1]`:           `[1]`,
+		`[1 2 "x"{}[]]`:  `[1,2,"x",{},[]]`,
+		`{"a":1 "b":2}`:  `{"a":1,"b":2}`,
+		`{"a":01}`:       `{"a":1}`,
+		`[00,-01.5,0e1]`: `[0,-1.5,0e1]`,
+	} {
+		o, err := djson.Parse([]byte(raw))
+		require.NoError(t, err, raw)
+		assert.Equal(t, expected, string(o.JSONLine()), raw)
+
+		o, err = djson.ParseWith([]byte(`{"x":`+raw+`}`), djson.ParseOptions{Lazy: true})
+		require.NoError(t, err, raw)
+		assert.Equal(t, `{"x":`+expected+`}`, string(o.JSONLine()), raw)
+
+		_, err = djson.ParseStrict([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+
+	for _, raw := range []string{`[1,,2]`, `{"a":1,,"b":2}`, `{"a" 1}`, `[1 x]`} {
+		_, err := djson.Parse([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+
+	v, err := djson.ParseValue([]byte(` "text" `))
+	require.NoError(t, err)
+	assert.Equal(t, "text", v)
+
+	v, err = djson.ParseValue([]byte(`-12.5`))
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("-12.5"), v)
+
+	a := djson.NewArray()
+	a.Append(v)
+	assert.Equal(t, -12.5, a.GetFloat("0", 0))
+	assert.Equal(t, "-12.5", a.GetString("0", ""))
+
+	v, err = djson.ParseValue([]byte(`true`))
+	require.NoError(t, err)
+	assert.Equal(t, true, v)
+
+	v, err = djson.ParseValue([]byte(`null`))
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = djson.ParseValue([]byte(`{"a":[1]}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.(*djson.DynamicJSON).GetInt("a/0", 0))
+
+	o := djson.NewMap()
+	o.Set("x", v)
+	assert.Equal(t, 1, o.GetInt("x/a/0", 0))
+
+	_, err = djson.ParseValueWith([]byte(`12 13`), djson.ParseOptions{Strict: true})
+	assert.Error(t, err)
+
+	_, err = djson.ParseValue([]byte(`nul`))
+	assert.Error(t, err)
+}
+
+func TestFromResponseValue(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"pong"`))
+	}))
+	defer srv.Close()
+
+	v, err := djson.FromResponseValue(http.Get(srv.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "pong", v)
+}
+
+func TestParseDuplicateKeys(t *testing.T) {
+
+	raw := []byte(`{"a":1,"b":2,"a":3,"c":{"x":[1]},"a":[4]}`)
+
+	o, err := djson.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[4],"b":2,"c":{"x":[1]}}`, string(o.JSONLine()))
+
+	o, err = djson.ParseWith(raw, djson.ParseOptions{DuplicateKeys: djson.DuplicateFirstWins})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":2,"c":{"x":[1]}}`, string(o.JSONLine()))
+
+	o, err = djson.ParseWith(raw, djson.ParseOptions{DuplicateKeys: djson.DuplicateCollect})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[1,3,[4]],"b":2,"c":{"x":[1]}}`, string(o.JSONLine()))
+
+	_, err = djson.ParseWith([]byte("{\"a\":1,\n \"a\":2}"), djson.ParseOptions{DuplicateKeys: djson.DuplicateError})
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 2, pe.Line)
+	assert.Equal(t, 2, pe.Column)
+	assert.Equal(t, `duplicate key "a"`, pe.Actual)
+}
@@ -0,0 +1,205 @@
+package djson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// pathNode is a tree of the paths requested from ParsePaths.
+type pathNode struct {
+	leaf     bool // the whole value is selected
+	children map[string]*pathNode
+}
+
+// ParsePaths returns a document containing only the given paths, the rest of
+// the input is validated but not built. Paths use the syntax of Get, missing
+// paths are ignored.
+func ParsePaths(data []byte, paths ...string) (*DynamicJSON, error) {
+	return ParsePathsWith(data, ParseOptions{}, paths...)
+}
+
+func ParsePathsWith(data []byte, opts ParseOptions, paths ...string) (*DynamicJSON, error) {
+
+	root := &pathNode{}
+	for _, path := range paths {
+		node := root
+		for _, name := range strings.Split(path, "/") {
+			if name == "" {
+				continue
+			}
+			child := node.children[name]
+			if child == nil {
+				if node.children == nil {
+					node.children = make(map[string]*pathNode)
+				}
+				child = &pathNode{}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.leaf = true
+	}
+
+	p := newBytesParser(data, opts)
+
+	c, _ := p.skipSpaces()
+	if c != '{' && c != '[' {
+		return nil, p.unexpected("'{' or '['")
+	}
+
+	v, _, err := p.selectValue(root)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DynamicJSON), nil
+}
+
+// selectValue builds the parts of the next value selected by node, found is
+// false if nothing is selected.
+func (p *parseState) selectValue(node *pathNode) (v any, found bool, err error) {
+
+	if node.leaf {
+		v, err = p.parseValue()
+		return v, err == nil, err
+	}
+
+	c, _ := p.skipSpaces()
+	if c != '{' && c != '[' {
+		return nil, false, p.skipValue()
+	}
+	p.pos++
+	if err = p.nest(); err != nil {
+		return nil, false, err
+	}
+	if c == '{' {
+		v, err = p.selectObject(node)
+	} else {
+		v, err = p.selectArray(node)
+	}
+	p.depth--
+
+	if err != nil {
+		return nil, false, err
+	}
+	return v, v.(*DynamicJSON).Len() > 0, nil
+}
+
+func (p *parseState) selectObject(node *pathNode) (any, error) {
+
+	r := NewMap()
+
+	c, ok := p.skipSpaces()
+	if !ok {
+		return p.truncated(r, "string key or '}'")
+	}
+	if c == '}' {
+		p.pos++
+		return r, nil
+	}
+
+	for {
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "string key")
+		}
+		if c != '"' {
+			return nil, p.unexpected("string key")
+		}
+		p.pos++
+
+		b, err := p.parseString('"')
+		if err != nil {
+			return nil, err
+		}
+		child := node.children[string(b)]
+		var key string
+		if child != nil {
+			key = string(b)
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "':'")
+		}
+		if c != ':' {
+			return nil, p.unexpected("':'")
+		}
+		p.pos++
+
+		if _, ok = p.skipSpaces(); !ok {
+			return p.truncated(r, "value")
+		}
+
+		if child != nil {
+			value, found, err := p.selectValue(child)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				r.set(key, value)
+			}
+		} else if err := p.skipValue(); err != nil {
+			return nil, err
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "',' or '}'")
+		}
+		p.pos++
+		switch c {
+		case ',':
+			continue
+		case '}':
+			return r, nil
+		}
+		p.pos--
+		return nil, p.unexpected("',' or '}'")
+	}
+}
+
+func (p *parseState) selectArray(node *pathNode) (any, error) {
+
+	r := NewArray()
+
+	c, ok := p.skipSpaces()
+	if !ok {
+		return p.truncated(r, "value or ']'")
+	}
+	if c == ']' {
+		p.pos++
+		return r, nil
+	}
+
+	for i := 0; ; i++ {
+		if _, ok = p.skipSpaces(); !ok {
+			return p.truncated(r, "value")
+		}
+
+		if child := node.children[strconv.Itoa(i)]; child != nil {
+			value, found, err := p.selectValue(child)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				r.SetI(i, value)
+			}
+		} else if err := p.skipValue(); err != nil {
+			return nil, err
+		}
+
+		c, ok = p.skipSpaces()
+		if !ok {
+			return p.truncated(r, "',' or ']'")
+		}
+		p.pos++
+		switch c {
+		case ',':
+			continue
+		case ']':
+			return r, nil
+		}
+		p.pos--
+		return nil, p.unexpected("',' or ']'")
+	}
+}
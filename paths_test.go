@@ -0,0 +1,31 @@
+package djson_test
+
+import (
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePaths(t *testing.T) {
+
+	raw := []byte(`{"meta":{"type":"click","ts":[1,2,{"x":"}"}]},"payload":{"big":[1,2,3]},"user":{"id":42,"name":"bob"},"list":[{"a":1},{"a":2,"b":3}]}`)
+
+	o, err := djson.ParsePaths(raw, "meta/type", "user/id", "list/1/a", "missing/path")
+	require.NoError(t, err)
+	assert.Equal(t, `{"meta":{"type":"click"},"user":{"id":42},"list":[null,{"a":2}]}`, string(o.JSONLine()))
+	assert.Equal(t, "click", o.GetStr("meta/type"))
+	assert.Equal(t, 42, o.GetInt("user/id", 0))
+
+	o, err = djson.ParsePaths(raw, "/payload")
+	require.NoError(t, err)
+	assert.Equal(t, `{"payload":{"big":[1,2,3]}}`, string(o.JSONLine()))
+
+	o, err = djson.ParsePaths(raw)
+	require.NoError(t, err)
+	assert.Equal(t, 0, o.Len())
+
+	_, err = djson.ParsePaths([]byte(`{"meta":{"type":"a"},"other":[1,}`), "meta/type")
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package djson
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Parser is a push parser, the input is fed in fragments of any size and
+// parsed as it arrives. Done returns the result, Close abandons the input.
+// A Parser dropped without either is released by the garbage collector.
+type Parser struct {
+	s *feedState
+}
+
+// feedState is shared with the parsing goroutine, which must not reference
+// the Parser to let it be collected.
+type feedState struct {
+	in      chan []byte
+	ready   chan struct{} // the fed fragment is consumed
+	done    chan struct{}
+	pending []byte
+	fed     bool
+	eof     bool
+
+	opts      ParseOptions
+	startOnce sync.Once
+	closeOnce sync.Once
+	closed    bool
+
+	d   *DynamicJSON
+	err error
+}
+
+// NewParser returns an idle parser. The first Feed starts a goroutine with a
+// 64 KB read buffer, every Feed hands the fragment over to it and waits. Done
+// or Close release both, a dropped Parser only when it is garbage collected.
+func NewParser() *Parser {
+	return NewParserWith(ParseOptions{})
+}
+
+func NewParserWith(opts ParseOptions) *Parser {
+
+	s := &feedState{
+		in:    make(chan []byte),
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+		opts:  opts,
+	}
+
+	self := &Parser{s: s}
+	runtime.SetFinalizer(self, func(self *Parser) {
+		self.s.close()
+	})
+	return self
+}
+
+// Feed passes the next fragment to the parser and returns after it has been
+// parsed, so data may be reused. A parse error is returned by the Feed call
+// which detected it.
+func (self *Parser) Feed(data []byte) error {
+
+	s := self.s
+	if s.closed {
+		return fmt.Errorf("Feed after Done or Close")
+	}
+	s.start()
+
+	select {
+	case s.in <- data:
+	case <-s.done:
+		return s.err
+	}
+
+	select {
+	case <-s.ready:
+		return nil
+	case <-s.done:
+		return s.err
+	}
+}
+
+// Done marks the end of input and returns the parsed document.
+func (self *Parser) Done() (*DynamicJSON, error) {
+	self.s.start()
+	self.s.close()
+	<-self.s.done
+	return self.s.d, self.s.err
+}
+
+// Close stops the parser without waiting for the rest of the input.
+func (self *Parser) Close() error {
+	self.s.startOnce.Do(func() {
+		// nothing was fed, there is no goroutine
+		self.s.err = fmt.Errorf("Done after Close")
+		close(self.s.done)
+	})
+	self.s.close()
+	<-self.s.done
+	return nil
+}
+
+// start runs the parsing goroutine.
+func (s *feedState) start() {
+	s.startOnce.Do(func() {
+		go func() {
+			defer close(s.done)
+			s.d, s.err = ParseReaderWith(s, s.opts)
+			if s.err == nil {
+				// the rest is ignored, Feed must not block on it
+				io.Copy(io.Discard, s)
+			}
+		}()
+	})
+}
+
+// close ends the input, the parsing goroutine exits.
+func (s *feedState) close() {
+	s.closeOnce.Do(func() {
+		s.closed = true
+		close(s.in)
+	})
+}
+
+// Read is the parser side of Feed, it runs in the parsing goroutine.
+func (s *feedState) Read(b []byte) (int, error) {
+
+	if s.eof {
+		return 0, io.EOF
+	}
+
+	if len(s.pending) == 0 {
+		if s.fed {
+			s.ready <- struct{}{}
+		}
+		data, ok := <-s.in
+		if !ok {
+			s.eof = true
+			return 0, io.EOF
+		}
+		s.pending = data
+		s.fed = true
+	}
+
+	n := copy(b, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package djson_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserFeed(t *testing.T) {
+
+	raw := `{"s":"abéc","n":-12.5e3,"a":[true,null],"u":"😀"}`
+
+	p := djson.NewParser()
+	for i := 0; i < len(raw); i++ {
+		require.NoError(t, p.Feed([]byte(raw[i:i+1])))
+	}
+	o, err := p.Done()
+	require.NoError(t, err)
+	assert.Equal(t, "abéc", o.GetStr("s"))
+	assert.Equal(t, -12500.0, o.GetFloat("n", 0))
+	assert.Equal(t, "😀", o.GetStr("u"))
+	assert.Equal(t, raw, string(o.JSONLine()))
+
+	p = djson.NewParser()
+	require.NoError(t, p.Feed([]byte(`{"a":1}`)))
+	require.NoError(t, p.Feed([]byte(`trailing`)))
+	o, err = p.Done()
+	require.NoError(t, err)
+	assert.Equal(t, 1, o.GetInt("a", 0))
+}
+
+func TestParserFeedErrors(t *testing.T) {
+
+	p := djson.NewParser()
+	require.NoError(t, p.Feed([]byte(`{"a":`)))
+	err := p.Feed([]byte(`x}`))
+	var pe *djson.ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "value", pe.Expected)
+
+	_, err = p.Done()
+	assert.Equal(t, pe, err)
+
+	p = djson.NewParserWith(djson.ParseOptions{Strict: true})
+	require.NoError(t, p.Feed([]byte(`{"a":[1`)))
+	_, err = p.Done()
+	assert.Error(t, err)
+}
+
+func TestParserAbandon(t *testing.T) {
+
+	before := runtime.NumGoroutine()
+
+	p := djson.NewParser()
+	require.NoError(t, p.Feed([]byte(`{"a":`)))
+	require.NoError(t, p.Close())
+	assert.Error(t, p.Feed([]byte(`1}`)))
+
+	running := runtime.NumGoroutine()
+	idle := make([]*djson.Parser, 100)
+	for i := range idle {
+		idle[i] = djson.NewParser()
+	}
+	assert.Equal(t, running, runtime.NumGoroutine())
+	require.NoError(t, idle[0].Close())
+	_, err := idle[0].Done()
+	assert.Error(t, err)
+
+	for i := 0; i < 100; i++ {
+		p := djson.NewParser()
+		require.NoError(t, p.Feed([]byte(`{"a":`)))
+	}
+
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
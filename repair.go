@@ -0,0 +1,57 @@
+package djson
+
+// RepairAction is a fix applied by ParseRepair.
+type RepairAction string
+
+const (
+	RepairClosedString RepairAction = "closed string"
+	RepairClosedArray  RepairAction = "closed array"
+	RepairClosedObject RepairAction = "closed object"
+	RepairDroppedComma RepairAction = "dropped dangling comma"
+	RepairDroppedKey   RepairAction = "dropped key without value"
+	RepairDroppedValue RepairAction = "dropped incomplete value"
+)
+
+type Repair struct {
+	Offset int64 // input offset where the fix was applied
+	Action RepairAction
+}
+
+// ParseRepair parses truncated or slightly malformed JSON: open strings, arrays
+// and objects are closed, dangling and stray commas, keys without values and
+// incomplete values are dropped, a missing closing bracket is inserted before
+// a mismatched one. The applied fixes are returned in the input order, none
+// for valid JSON.
+func ParseRepair(data []byte) (*DynamicJSON, []Repair, error) {
+	return ParseRepairWith(data, ParseOptions{})
+}
+
+// ParseRepairWith is ParseRepair with options, Strict is ignored.
+func ParseRepairWith(data []byte, opts ParseOptions) (*DynamicJSON, []Repair, error) {
+
+	var repairs []Repair
+	opts.Strict = false
+	p := newBytesParser(data, opts)
+	p.repairs = &repairs
+
+	d, err := p.parseDocument()
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, repairs, nil
+}
+
+func (p *parseState) repair(action RepairAction) {
+	if p.repairs != nil {
+		*p.repairs = append(*p.repairs, Repair{Offset: p.offset(), Action: action})
+	}
+}
+
+// repairable is true if the input ended in the repair mode.
+func (p *parseState) repairable() bool {
+	if p.repairs == nil || p.err != nil {
+		return false
+	}
+	_, ok := p.peek()
+	return !ok
+}
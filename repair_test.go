@@ -0,0 +1,51 @@
+package djson_test
+
+import (
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepair(t *testing.T) {
+
+	for _, tc := range []struct {
+		raw      string
+		expected string
+		actions  []djson.RepairAction
+	}{
+		{`{"a":1}`, `{"a":1}`, nil},
+		{`{"a":"hel`, `{"a":"hel"}`, []djson.RepairAction{djson.RepairClosedString, djson.RepairClosedObject}},
+		{`{"a":[1,2`, `{"a":[1,2]}`, []djson.RepairAction{djson.RepairClosedArray, djson.RepairClosedObject}},
+		{`{"a":[1,2,`, `{"a":[1,2]}`, []djson.RepairAction{djson.RepairDroppedComma, djson.RepairClosedArray, djson.RepairClosedObject}},
+		{`{"a":[1,2,],}`, `{"a":[1,2]}`, []djson.RepairAction{djson.RepairDroppedComma, djson.RepairDroppedComma}},
+		{`{"a":1,"b":`, `{"a":1}`, []djson.RepairAction{djson.RepairDroppedKey, djson.RepairClosedObject}},
+		{`{"a":1,"b`, `{"a":1}`, []djson.RepairAction{djson.RepairClosedString, djson.RepairDroppedKey, djson.RepairClosedObject}},
+		{`[1,tr`, `[1]`, []djson.RepairAction{djson.RepairDroppedValue, djson.RepairClosedArray}},
+		{`["a\u00`, `["a"]`, []djson.RepairAction{djson.RepairClosedString, djson.RepairClosedArray}},
+		{`{"a":[1,2}`, `{"a":[1,2]}`, []djson.RepairAction{djson.RepairClosedArray}},
+		{`{"a":1,"b":}`, `{"a":1}`, []djson.RepairAction{djson.RepairDroppedKey}},
+		{`{"a":,"b":2}`, `{"b":2}`, []djson.RepairAction{djson.RepairDroppedKey}},
+		{`{"a":1,,"b":2}`, `{"a":1,"b":2}`, []djson.RepairAction{djson.RepairDroppedComma}},
+		{`[1,2,,3]`, `[1,2,3]`, []djson.RepairAction{djson.RepairDroppedComma}},
+		{`[,1]`, `[1]`, []djson.RepairAction{djson.RepairDroppedComma}},
+	} {
+		o, repairs, err := djson.ParseRepair([]byte(tc.raw))
+		require.NoError(t, err, tc.raw)
+		assert.Equal(t, tc.expected, string(o.JSONLine()), tc.raw)
+
+		var actions []djson.RepairAction
+		for _, r := range repairs {
+			actions = append(actions, r.Action)
+		}
+		assert.Equal(t, tc.actions, actions, tc.raw)
+	}
+
+	_, repairs, err := djson.ParseRepair([]byte(`{"a":[1,`))
+	require.NoError(t, err)
+	assert.EqualValues(t, 8, repairs[0].Offset)
+
+	_, _, err = djson.ParseRepair([]byte(`{"a":x}`))
+	assert.Error(t, err)
+}
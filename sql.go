@@ -0,0 +1,66 @@
+package djson
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements sql.Scanner for JSON columns, NULL and JSON null clear the
+// document. Use NullJSON to tell NULL apart.
+func (self *DynamicJSON) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("Scan of %T into djson", src)
+	}
+
+	if src == nil || string(bytes.TrimSpace(data)) == "null" {
+		if self.IsFrozen() {
+			return fmt.Errorf("Modification attempt of frozen djson")
+		}
+		self.Clear()
+		return nil
+	}
+	return self.UnmarshalJSON(data)
+}
+
+// Value implements driver.Valuer, a nil document is NULL. The JSON is passed
+// as a string, drivers like lib/pq send []byte as bytea which jsonb rejects.
+func (self *DynamicJSON) Value() (driver.Value, error) {
+	if self == nil {
+		return nil, nil
+	}
+	return string(self.JSONLine()), nil
+}
+
+// NullJSON is a nullable JSON column like sql.NullString.
+type NullJSON struct {
+	JSON  *DynamicJSON
+	Valid bool // JSON is not NULL
+}
+
+func (self *NullJSON) Scan(src any) error {
+	if src == nil {
+		self.JSON, self.Valid = nil, false
+		return nil
+	}
+	d := NewMap()
+	if err := d.Scan(src); err != nil {
+		return err
+	}
+	self.JSON, self.Valid = d, true
+	return nil
+}
+
+func (self NullJSON) Value() (driver.Value, error) {
+	if !self.Valid {
+		return nil, nil
+	}
+	return self.JSON.Value()
+}
@@ -0,0 +1,121 @@
+package djson_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memDriver stores one value per key: "put" takes key and value, "get" takes key.
+type memDriver struct {
+	mu   sync.Mutex
+	data map[int64]driver.Value
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) { return memConn{d}, nil }
+
+type memConn struct{ d *memDriver }
+
+func (c memConn) Prepare(query string) (driver.Stmt, error) { return memStmt{c.d, query}, nil }
+func (c memConn) Close() error                              { return nil }
+func (c memConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type memStmt struct {
+	d     *memDriver
+	query string
+}
+
+func (s memStmt) Close() error  { return nil }
+func (s memStmt) NumInput() int { return -1 }
+
+func (s memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.data[args[0].(int64)] = args[1]
+	return driver.RowsAffected(1), nil
+}
+
+func (s memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &memRows{value: s.d.data[args[0].(int64)]}, nil
+}
+
+type memRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *memRows) Columns() []string { return []string{"doc"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func init() {
+	sql.Register("djson-mem", &memDriver{data: make(map[int64]driver.Value)})
+}
+
+func TestSQL(t *testing.T) {
+
+	db, err := sql.Open("djson-mem", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	o, err := djson.Parse([]byte(`{"b":1,"a":[true,"x"]}`))
+	require.NoError(t, err)
+
+	v, err := o.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":1,"a":[true,"x"]}`, v)
+
+	_, err = db.Exec("put", 1, o)
+	require.NoError(t, err)
+	_, err = db.Exec("put", 2, `{"s":"text column"}`)
+	require.NoError(t, err)
+	_, err = db.Exec("put", 3, djson.NullJSON{})
+	require.NoError(t, err)
+
+	var d djson.DynamicJSON
+	require.NoError(t, db.QueryRow("get", 1).Scan(&d))
+	assert.Equal(t, `{"b":1,"a":[true,"x"]}`, string(d.JSONLine()))
+
+	require.NoError(t, db.QueryRow("get", 2).Scan(&d))
+	assert.Equal(t, "text column", d.GetStr("s"))
+
+	var n djson.NullJSON
+	require.NoError(t, db.QueryRow("get", 3).Scan(&n))
+	assert.False(t, n.Valid)
+	assert.Nil(t, n.JSON)
+
+	require.NoError(t, db.QueryRow("get", 1).Scan(&n))
+	assert.True(t, n.Valid)
+	assert.Equal(t, 1, n.JSON.GetInt("b", 0))
+
+	require.NoError(t, db.QueryRow("get", 3).Scan(&d))
+	assert.Equal(t, 0, d.Len())
+
+	assert.Error(t, d.Scan(42))
+
+	require.NoError(t, d.Scan(`{"old":1}`))
+	require.NoError(t, d.Scan([]byte(" null ")))
+	assert.Equal(t, 0, d.Len())
+
+	require.NoError(t, d.Scan(`{"old":1}`))
+	d.Freeze()
+	assert.Error(t, d.Scan(nil))
+	assert.Error(t, d.Scan("null"))
+	assert.Equal(t, 1, d.GetInt("old", 0))
+}
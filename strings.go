@@ -3,8 +3,9 @@ package djson
 // Taken from https://github.com/segmentio/encoding
 
 import (
-	"bytes"
+	"fmt"
 	"math/bits"
+	"unicode/utf16"
 	"unicode/utf8"
 	"unsafe"
 )
@@ -81,24 +82,24 @@ func stringToUint64(s string) []uint64 {
 
 const gHex = "0123456789abcdef"
 
-func encodeString(b *bytes.Buffer, s string) {
+func appendString(b []byte, s string, opts *EncodeOptions) ([]byte, error) {
 
 	if len(s) == 0 {
-		b.WriteString(`""`)
-		return
+		b = append(b, `""`...)
+		return b, nil
 	}
 	i := 0
 	j := 0
 
-	escapeHTML := true
+	escapeHTML := opts.EscapeHTML
 
-	b.WriteByte('"')
+	b = append(b, '"')
 
 	if len(s) >= 8 {
 		if j = escapeIndex(s, escapeHTML); j < 0 {
-			b.WriteString(s)
-			b.WriteByte('"')
-			return
+			b = append(b, s...)
+			b = append(b, '"')
+			return b, nil
 		}
 	}
 
@@ -113,39 +114,39 @@ func encodeString(b *bytes.Buffer, s string) {
 
 		switch c {
 		case '\\', '"':
-			b.WriteString(s[i:j])
-			b.WriteByte('\\')
-			b.WriteByte(c)
+			b = append(b, s[i:j]...)
+			b = append(b, '\\')
+			b = append(b, c)
 			i = j + 1
 			j = j + 1
 			continue
 
 		case '\n':
-			b.WriteString(s[i:j])
-			b.WriteString(`\n`)
+			b = append(b, s[i:j]...)
+			b = append(b, `\n`...)
 			i = j + 1
 			j = j + 1
 			continue
 
 		case '\r':
-			b.WriteString(s[i:j])
-			b.WriteString(`\r`)
+			b = append(b, s[i:j]...)
+			b = append(b, `\r`...)
 			i = j + 1
 			j = j + 1
 			continue
 
 		case '\t':
-			b.WriteString(s[i:j])
-			b.WriteString(`\t`)
+			b = append(b, s[i:j]...)
+			b = append(b, `\t`...)
 			i = j + 1
 			j = j + 1
 			continue
 
 		case '<', '>', '&':
-			b.WriteString(s[i:j])
-			b.WriteString(`\u00`)
-			b.WriteByte(gHex[c>>4])
-			b.WriteByte(gHex[c&0xF])
+			b = append(b, s[i:j]...)
+			b = append(b, `\u00`...)
+			b = append(b, gHex[c>>4])
+			b = append(b, gHex[c&0xF])
 			i = j + 1
 			j = j + 1
 			continue
@@ -153,10 +154,10 @@ func encodeString(b *bytes.Buffer, s string) {
 
 		// This encodes bytes < 0x20 except for \t, \n and \r.
 		if c < 0x20 {
-			b.WriteString(s[i:j])
-			b.WriteString(`\u00`)
-			b.WriteByte(gHex[c>>4])
-			b.WriteByte(gHex[c&0xF])
+			b = append(b, s[i:j]...)
+			b = append(b, `\u00`...)
+			b = append(b, gHex[c>>4])
+			b = append(b, gHex[c&0xF])
 			i = j + 1
 			j = j + 1
 			continue
@@ -165,8 +166,20 @@ func encodeString(b *bytes.Buffer, s string) {
 		r, size := utf8.DecodeRuneInString(s[j:])
 
 		if r == utf8.RuneError && size == 1 {
-			b.WriteString(s[i:j])
-			b.WriteString(`\ufffd`)
+			b = append(b, s[i:j]...)
+			switch opts.UTF8 {
+			case UTF8Reject:
+				return b, fmt.Errorf("invalid UTF-8 at byte %d of %q", j, s)
+			case UTF8PassThrough:
+				if r, ok := decodeSurrogate(s[j:]); ok {
+					b = appendEscapedRune(b, r)
+					size = 3
+				} else {
+					b = append(b, c)
+				}
+			default:
+				b = append(b, `\ufffd`...)
+			}
 			i = j + size
 			j = j + size
 			continue
@@ -181,9 +194,21 @@ func encodeString(b *bytes.Buffer, s string) {
 			// and can lead to security holes there. It is valid JSON to
 			// escape them, so we do so unconditionally.
 			// See http://timelessrepo.com/json-isnt-a-javascript-subset for discussion.
-			b.WriteString(s[i:j])
-			b.WriteString(`\u202`)
-			b.WriteByte(gHex[r&0xF])
+			b = append(b, s[i:j]...)
+			b = append(b, `\u202`...)
+			b = append(b, gHex[r&0xF])
+			i = j + size
+			j = j + size
+			continue
+		}
+
+		if opts.ASCIIOnly {
+			b = append(b, s[i:j]...)
+			if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+				b = appendEscapedRune(b, r1)
+				r = r2
+			}
+			b = appendEscapedRune(b, r)
 			i = j + size
 			j = j + size
 			continue
@@ -192,6 +217,25 @@ func encodeString(b *bytes.Buffer, s string) {
 		j += size
 	}
 
-	b.WriteString(s[i:])
-	b.WriteByte('"')
+	b = append(b, s[i:]...)
+	b = append(b, '"')
+	return b, nil
+}
+
+func appendEscapedRune(b []byte, r rune) []byte {
+	return append(b, '\\', 'u', gHex[r>>12&0xF], gHex[r>>8&0xF], gHex[r>>4&0xF], gHex[r&0xF])
+}
+
+// appendSurrogate appends the WTF-8 bytes of a lone surrogate, utf8.AppendRune
+// would write U+FFFD.
+func appendSurrogate(b []byte, r rune) []byte {
+	return append(b, 0xe0|byte(r>>12), 0x80|byte(r>>6)&0x3f, 0x80|byte(r)&0x3f)
+}
+
+// decodeSurrogate decodes a surrogate stored by appendSurrogate.
+func decodeSurrogate(s string) (rune, bool) {
+	if len(s) < 3 || s[0] != 0xed || s[1] < 0xa0 || s[1] > 0xbf || s[2]&0xc0 != 0x80 {
+		return 0, false
+	}
+	return 0xd000 | rune(s[1]&0x3f)<<6 | rune(s[2]&0x3f), true
 }
@@ -0,0 +1,79 @@
+package djson
+
+import (
+	"strings"
+	"time"
+)
+
+// Time is a string value converted by ParseOptions.TimeLayouts, it is written
+// back in its layout.
+type Time struct {
+	time.Time
+	Layout string
+}
+
+func (self Time) String() string {
+	return self.Format(self.Layout)
+}
+
+// parseTime converts s if it is exactly a time in one of the layouts.
+func parseTime(s string, layouts []string) (Time, bool) {
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil && t.Format(layout) == s {
+			return Time{Time: t, Layout: layout}, true
+		}
+	}
+	return Time{}, false
+}
+
+// convertTimes applies ParseOptions.TimePaths to a parsed document.
+func convertTimes(d *DynamicJSON, opts *ParseOptions) {
+	for _, path := range opts.TimePaths {
+		var parts []string
+		for _, name := range strings.Split(path, "/") {
+			if name != "" {
+				parts = append(parts, name)
+			}
+		}
+		if len(parts) != 0 {
+			convertTimesAt(d, parts, opts.TimeLayouts)
+		}
+	}
+}
+
+func convertTimesAt(d *DynamicJSON, parts []string, layouts []string) {
+
+	convert := func(i int) {
+		switch v := d.at(i).(type) {
+		case *DynamicJSON:
+			if len(parts) > 1 {
+				convertTimesAt(v, parts[1:], layouts)
+			}
+		case string:
+			if len(parts) == 1 {
+				if t, ok := parseTime(v, layouts); ok {
+					d.values[i] = t
+				}
+			}
+		}
+	}
+
+	name := parts[0]
+	switch {
+	case name == "*":
+		for i := range d.values {
+			if d.values[i] != gDeletedEntry {
+				convert(i)
+			}
+		}
+	case d.IsArray():
+		if i := key2Index(name); i >= 0 && i < len(d.values) {
+			convert(i)
+		}
+	default:
+		if i, ok := d.keys[name]; ok {
+			convert(int(i))
+		}
+	}
+}
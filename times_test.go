@@ -0,0 +1,45 @@
+package djson_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gavriva/djson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimes(t *testing.T) {
+
+	raw := `{"at":"2024-03-01T10:20:30.5Z","day":"2024-03-01","name":"2024","list":[{"ts":"2024-03-01T10:20:30+02:00"}]}`
+	layouts := []string{time.RFC3339Nano, time.DateOnly}
+
+	o, err := djson.ParseWith([]byte(raw), djson.ParseOptions{TimeLayouts: layouts})
+	require.NoError(t, err)
+
+	at, ok := o.Get("at").(djson.Time)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 20, 30, 500e6, time.UTC), at.Time)
+	assert.Equal(t, time.DateOnly, o.Get("day").(djson.Time).Layout)
+	assert.Equal(t, "2024", o.Get("name"))
+	assert.Equal(t, 2024, o.GetTime("list/0/ts").Year())
+	assert.Equal(t, "2024-03-01", o.GetStr("day"))
+	assert.Equal(t, raw, string(o.JSONLine()))
+
+	o, err = djson.ParseWith([]byte(`{"at":"2024-03-01T10:20:30.500Z"}`), djson.ParseOptions{TimeLayouts: layouts})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01T10:20:30.500Z", o.Get("at"))
+
+	o, err = djson.ParseWith([]byte(raw), djson.ParseOptions{TimeLayouts: layouts, TimePaths: []string{"list/*/ts", "day"}, Lazy: true})
+	require.NoError(t, err)
+	assert.IsType(t, "", o.Get("at"))
+	assert.IsType(t, djson.Time{}, o.Get("day"))
+	assert.IsType(t, djson.Time{}, o.Get("list/0/ts"))
+	assert.Equal(t, raw, string(o.JSONLine()))
+
+	v, err := djson.ParseValueWith([]byte(`{"t":"2024-03-01","u":"2024-03-01"}`), djson.ParseOptions{TimeLayouts: layouts, TimePaths: []string{"t"}})
+	require.NoError(t, err)
+	o = v.(*djson.DynamicJSON)
+	assert.IsType(t, djson.Time{}, o.Get("t"))
+	assert.IsType(t, "", o.Get("u"))
+}